@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"time"
 
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
 var (
 	addName        string
 	addDescription string
+	addParent      int
+	addTags        []string
+	addPriority    string
+	addDue         string
 )
 
 // addCmd represents the add command
@@ -28,12 +33,29 @@ var addCmd = &cobra.Command{
 			return fmt.Errorf("task name is required (use --name or -n)")
 		}
 
-		store, err := task.NewTaskStore()
+		priority, err := uni.ParsePriority(addPriority)
+		if err != nil {
+			return err
+		}
+		due, err := parseDueFlag(addDue)
 		if err != nil {
 			return err
 		}
 
-		newTask, err := store.AddTask(addName, addDescription)
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		newTask, err := client.Create(cmd.Context(), uni.TaskSpec{
+			Name:        addName,
+			Description: addDescription,
+			Parent:      addParent,
+			Tags:        addTags,
+			Priority:    priority,
+			DueAt:       due,
+		})
 		if err != nil {
 			return err
 		}
@@ -43,13 +65,29 @@ var addCmd = &cobra.Command{
 			return nil
 		}
 
-		return output.FormatTask(newTask, GetOutputFormat())
+		return uni.WriteTask(os.Stdout, newTask, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()})
 	},
 }
 
+// parseDueFlag parses a --due value; empty input means no due date
+func parseDueFlag(input string) (*time.Time, error) {
+	if input == "" {
+		return nil, nil
+	}
+	due, err := uni.ParseDue(input, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
+
 func init() {
 	addCmd.Flags().StringVarP(&addName, "name", "n", "", "Task name (required)")
 	addCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Task description (optional)")
+	addCmd.Flags().IntVar(&addParent, "parent", 0, "ID of the parent task, making this a subtask (optional)")
+	addCmd.Flags().StringArrayVarP(&addTags, "tag", "t", nil, "Tag to attach (repeatable)")
+	addCmd.Flags().StringVarP(&addPriority, "priority", "P", "", "Priority, P0 (most urgent) to P3 (optional)")
+	addCmd.Flags().StringVar(&addDue, "due", "", "Due date: today, tomorrow, fri, +3d, +2w or YYYY-MM-DD (optional)")
 	addCmd.MarkFlagRequired("name")
 	rootCmd.AddCommand(addCmd)
 }
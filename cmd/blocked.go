@@ -1,50 +1,30 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
 // blockedCmd represents the blocked command
 var blockedCmd = &cobra.Command{
-	Use:     "blocked <id>",
+	Use:     "blocked [id|range]...",
 	Aliases: []string{"b"},
-	Short:   "Mark a task as blocked",
-	Long:    `Mark a task as blocked by providing its ID.`,
-	Args:    cobra.ExactArgs(1),
+	Short:   "Mark tasks as blocked",
+	Long: `Mark tasks as blocked by providing their IDs or a query.
+
+` + batchHelp + `
+This is a shortcut for "uni move <id>... blocked".`,
+	Example: `  uni blocked 3
+  uni blocked 3 5 7-12
+  uni blocked --where 'tag:sprint-4'`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
-			return err
-		}
-
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid task ID: %s", args[0])
-		}
-
-		store, err := task.NewTaskStore()
-		if err != nil {
-			return err
-		}
-
-		updatedTask, err := store.UpdateTaskStatus(id, task.StatusBlocked)
-		if err != nil {
-			return err
-		}
-
-		if GetOutputFormat() == "normal" {
-			fmt.Printf("Task #%d marked as blocked.\n", updatedTask.ID)
-			return nil
-		}
-
-		return output.FormatTask(updatedTask, GetOutputFormat())
+		return runStatusChange(cmd.Context(), args, uni.StatusBlocked, "blocked")
 	},
 }
 
 func init() {
+	addReasonFlag(blockedCmd)
+	addBatchFlags(blockedCmd)
 	rootCmd.AddCommand(blockedCmd)
 }
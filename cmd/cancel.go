@@ -1,50 +1,31 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
 // cancelCmd represents the cancel command
 var cancelCmd = &cobra.Command{
-	Use:     "cancel <id>",
+	Use:     "cancel [id|range]...",
 	Aliases: []string{"c"},
-	Short:   "Mark a task as cancelled",
-	Long:    `Mark a task as cancelled by providing its ID.`,
-	Args:    cobra.ExactArgs(1),
+	Short:   "Mark tasks as cancelled",
+	Long: `Mark tasks as cancelled by providing their IDs or a query.
+
+` + batchHelp + `
+This is a shortcut for "uni move <id>... cancel".`,
+	Example: `  uni cancel 3
+  uni cancel 3 5 7-12
+  uni cancel --where 'tag:sprint-4'`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
-			return err
-		}
-
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid task ID: %s", args[0])
-		}
-
-		store, err := task.NewTaskStore()
-		if err != nil {
-			return err
-		}
-
-		updatedTask, err := store.UpdateTaskStatus(id, task.StatusCancel)
-		if err != nil {
-			return err
-		}
-
-		if GetOutputFormat() == "normal" {
-			fmt.Printf("Task #%d marked as cancelled.\n", updatedTask.ID)
-			return nil
-		}
-
-		return output.FormatTask(updatedTask, GetOutputFormat())
+		return runStatusChange(cmd.Context(), args, uni.StatusCancel, "cancelled")
 	},
 }
 
 func init() {
+	addReasonFlag(cancelCmd)
+	addBatchFlags(cancelCmd)
+	addCascadeFlag(cancelCmd)
 	rootCmd.AddCommand(cancelCmd)
 }
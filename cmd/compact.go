@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+// compactCmd represents the compact command
+var compactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Fold the event log into a snapshot",
+	Long: `Fold all changes recorded in the event log into snapshot.json and empty the log.
+
+Only backends that keep a change log (events) support compaction.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		n, err := client.Compact(cmd.Context())
+		if errors.Is(err, uni.ErrUnsupported) {
+			return fmt.Errorf("the current store backend does not support compaction")
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Compacted %d events into the snapshot.\n", n)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compactCmd)
+}
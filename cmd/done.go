@@ -1,50 +1,31 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
 // doneCmd represents the done command
 var doneCmd = &cobra.Command{
-	Use:     "done <id>",
+	Use:     "done [id|range]...",
 	Aliases: []string{"d"},
-	Short:   "Mark a task as done",
-	Long:    `Mark a task as done by providing its ID.`,
-	Args:    cobra.ExactArgs(1),
+	Short:   "Mark tasks as done",
+	Long: `Mark tasks as done by providing their IDs or a query.
+
+` + batchHelp + `
+This is a shortcut for "uni move <id>... done".`,
+	Example: `  uni done 3
+  uni done 3 5 7-12
+  uni done --where 'tag:sprint-4'`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
-			return err
-		}
-
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid task ID: %s", args[0])
-		}
-
-		store, err := task.NewTaskStore()
-		if err != nil {
-			return err
-		}
-
-		updatedTask, err := store.UpdateTaskStatus(id, task.StatusDone)
-		if err != nil {
-			return err
-		}
-
-		if GetOutputFormat() == "normal" {
-			fmt.Printf("Task #%d marked as done.\n", updatedTask.ID)
-			return nil
-		}
-
-		return output.FormatTask(updatedTask, GetOutputFormat())
+		return runStatusChange(cmd.Context(), args, uni.StatusDone, "done")
 	},
 }
 
 func init() {
+	addReasonFlag(doneCmd)
+	addBatchFlags(doneCmd)
+	addCascadeFlag(doneCmd)
 	rootCmd.AddCommand(doneCmd)
 }
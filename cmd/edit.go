@@ -4,35 +4,66 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
+var (
+	editPriority string
+	editDue      string
+)
+
+// editedFields holds the values read back from the edited file
+type editedFields struct {
+	Name        string
+	Description string
+	Priority    string
+	Due         string
+}
+
 // editCmd represents the edit command
 var editCmd = &cobra.Command{
 	Use:     "edit <id>",
 	Aliases: []string{"e"},
 	Short:   "Edit a task using your default editor",
-	Long:    `Edit a task by opening it in your default editor (set via EDITOR environment variable).`,
-	Args:    cobra.ExactArgs(1),
+	Long: `Edit a task by opening it in your default editor (set via EDITOR environment variable).
+With --priority or --due the fields are changed directly without opening the editor.`,
+	Example: `  uni edit 5
+  uni edit api#12
+  uni edit 5 --priority P1 --due fri
+  uni edit 5 --due none`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id, err := strconv.Atoi(args[0])
+		client, ref, err := openTaskRef(cmd.Context(), args[0])
 		if err != nil {
-			return fmt.Errorf("invalid task ID: %s", args[0])
+			return err
 		}
+		defer client.Close()
 
-		store, err := task.NewTaskStore()
+		taskToEdit, err := client.Get(cmd.Context(), ref.ID)
 		if err != nil {
 			return err
 		}
 
-		taskToEdit, err := store.GetTask(id)
-		if err != nil {
-			return err
+		if cmd.Flags().Changed("priority") || cmd.Flags().Changed("due") {
+			if cmd.Flags().Changed("priority") {
+				if taskToEdit.Priority, err = uni.ParsePriority(editPriority); err != nil {
+					return err
+				}
+			}
+			if cmd.Flags().Changed("due") {
+				if taskToEdit.DueAt, err = parseDueEdit(editDue); err != nil {
+					return err
+				}
+			}
+			if err := client.Update(cmd.Context(), taskToEdit); err != nil {
+				return fmt.Errorf("failed to update task: %v", err)
+			}
+			fmt.Printf("Task %s updated successfully.\n", ref)
+			return nil
 		}
 
 		// Get editor from environment, default to vi
@@ -42,14 +73,19 @@ var editCmd = &cobra.Command{
 		}
 
 		// Create a temporary file with current task content
-		tempFile, err := os.CreateTemp("", fmt.Sprintf("uni-task-%d-*.txt", id))
+		tempFile, err := os.CreateTemp("", fmt.Sprintf("uni-task-%d-*.txt", ref.ID))
 		if err != nil {
 			return fmt.Errorf("failed to create temporary file: %v", err)
 		}
 		defer os.Remove(tempFile.Name())
 
 		// Write current task content to temp file
-		content := fmt.Sprintf("Name: %s\nDescription: %s\n", taskToEdit.Name, taskToEdit.Description)
+		due := ""
+		if taskToEdit.DueAt != nil {
+			due = taskToEdit.DueAt.Format(uni.DueDateLayout)
+		}
+		content := fmt.Sprintf("Name: %s\nDescription: %s\nPriority: %s\nDue: %s\n",
+			taskToEdit.Name, taskToEdit.Description, taskToEdit.Priority, due)
 		if _, err := tempFile.WriteString(content); err != nil {
 			return fmt.Errorf("failed to write to temporary file: %v", err)
 		}
@@ -90,45 +126,65 @@ var editCmd = &cobra.Command{
 		}
 
 		// Parse the edited content
-		newName, newDescription, err := parseEditedContent(string(editedContent))
+		fields, err := parseEditedContent(string(editedContent))
 		if err != nil {
 			return err
 		}
 
 		// Update the task
-		taskToEdit.Name = newName
-		taskToEdit.Description = newDescription
-		taskToEdit.UpdatedAt = time.Now()
+		taskToEdit.Name = fields.Name
+		taskToEdit.Description = fields.Description
+		if taskToEdit.Priority, err = uni.ParsePriority(fields.Priority); err != nil {
+			return err
+		}
+		if taskToEdit.DueAt, err = parseDueEdit(fields.Due); err != nil {
+			return err
+		}
 
 		// Update task in store
-		if err := store.UpdateTask(taskToEdit); err != nil {
+		if err := client.Update(cmd.Context(), taskToEdit); err != nil {
 			return fmt.Errorf("failed to update task: %v", err)
 		}
 
-		fmt.Printf("Task #%d updated successfully.\n", taskToEdit.ID)
+		fmt.Printf("Task %s updated successfully.\n", ref)
 		return nil
 	},
 }
 
-func parseEditedContent(content string) (name, description string, err error) {
+func parseEditedContent(content string) (editedFields, error) {
+	var fields editedFields
 	lines := strings.Split(content, "\n")
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "Name: ") {
-			name = strings.TrimPrefix(line, "Name: ")
+			fields.Name = strings.TrimPrefix(line, "Name: ")
 		} else if strings.HasPrefix(line, "Description: ") {
-			description = strings.TrimPrefix(line, "Description: ")
+			fields.Description = strings.TrimPrefix(line, "Description: ")
+		} else if strings.HasPrefix(line, "Priority:") {
+			fields.Priority = strings.TrimSpace(strings.TrimPrefix(line, "Priority:"))
+		} else if strings.HasPrefix(line, "Due:") {
+			fields.Due = strings.TrimSpace(strings.TrimPrefix(line, "Due:"))
 		}
 	}
 
-	if name == "" {
-		return "", "", fmt.Errorf("task name cannot be empty")
+	if fields.Name == "" {
+		return fields, fmt.Errorf("task name cannot be empty")
 	}
 
-	return name, description, nil
+	return fields, nil
+}
+
+// parseDueEdit parses an edited due date; empty input or "none" clears it
+func parseDueEdit(input string) (*time.Time, error) {
+	if strings.EqualFold(strings.TrimSpace(input), "none") {
+		return nil, nil
+	}
+	return parseDueFlag(strings.TrimSpace(input))
 }
 
 func init() {
+	editCmd.Flags().StringVarP(&editPriority, "priority", "P", "", "Set the priority, P0 to P3 (none clears it)")
+	editCmd.Flags().StringVar(&editDue, "due", "", "Set the due date: today, tomorrow, fri, +3d, +2w or YYYY-MM-DD (none clears it)")
 	rootCmd.AddCommand(editCmd)
 }
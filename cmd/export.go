@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export --format <format> [query]",
+	Short: "Export tasks to stdout in a file format",
+	Long: `Write all tasks, or those matching a query (see uni list --help), to stdout
+in any output format. With --format ics the tasks become VTODO entries of an
+iCalendar file that calendar apps can import or subscribe to: statuses map to
+NEEDS-ACTION, IN-PROCESS (working), COMPLETED (done) and CANCELLED (cancel),
+P0-P3 to priorities 1, 3, 5 and 7, and due dates to DUE.`,
+	Example: `  uni export --format ics > tasks.ics
+  uni export --format ics 'is:left due!=none' > due.ics
+  uni export --format csv > tasks.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ValidateOutputFormat(exportFormat); err != nil {
+			return err
+		}
+
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		tasks, err := client.List(cmd.Context(), uni.ListOptions{
+			Query:  strings.Join(args, " "),
+			Left:   GetShowLeft(),
+			Closed: GetShowClosed(),
+		})
+		if err != nil {
+			return err
+		}
+
+		return uni.WriteTasks(os.Stdout, tasks, exportFormat, uni.FormatOptions{Workflow: client.Workflow()})
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "ics", "Format to export (ics, json, yaml, csv, tsv, markdown, ...)")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
+	"os"
 
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
@@ -13,29 +11,28 @@ import (
 var getCmd = &cobra.Command{
 	Use:   "get <id>",
 	Short: "Get a specific task",
-	Long:  `Get a specific task by providing its ID.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Get a specific task by providing its ID.
+
+The ID may be qualified with the name of a registered store, e.g. api#12,
+to read a task of another repository (see uni stores).`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
 			return err
 		}
 
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid task ID: %s", args[0])
-		}
-
-		store, err := task.NewTaskStore()
+		client, ref, err := openTaskRef(cmd.Context(), args[0])
 		if err != nil {
 			return err
 		}
+		defer client.Close()
 
-		t, err := store.GetTask(id)
+		t, err := client.Get(cmd.Context(), ref.ID)
 		if err != nil {
 			return err
 		}
 
-		return output.FormatTask(t, GetOutputFormat())
+		return uni.WriteTask(os.Stdout, t, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()})
 	},
 }
 
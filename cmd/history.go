@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show the status history of a task",
+	Long:  `Show every status change of a task with its time, reason and how long the task stayed in the previous status.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
+			return err
+		}
+
+		client, ref, err := openTaskRef(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		t, err := client.Get(cmd.Context(), ref.ID)
+		if err != nil {
+			return err
+		}
+
+		return uni.WriteHistory(os.Stdout, t, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
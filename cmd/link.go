@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var linkBlockedBy []int
+
+// linkCmd represents the link command
+var linkCmd = &cobra.Command{
+	Use:   "link <id>",
+	Short: "Mark a task as blocked by other tasks",
+	Long: `Record that a task is blocked by one or more other tasks.
+
+While any blocker is still open the task is moved to blocked. Once the last
+blocker is closed the task goes back to the status it had before. Links that
+would create a dependency cycle are refused.`,
+	Example: `  uni link 5 --blocked-by 3
+  uni link 5 --blocked-by 3,4`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLink(cmd.Context(), args[0], func(client *uni.Client, id int) (*uni.Task, error) {
+			return client.Link(cmd.Context(), id, linkBlockedBy)
+		})
+	},
+}
+
+// unlinkCmd represents the unlink command
+var unlinkCmd = &cobra.Command{
+	Use:     "unlink <id>",
+	Short:   "Remove blockers from a task",
+	Long:    `Remove blocked-by links from a task. If no open blocker is left the task is unblocked.`,
+	Example: `  uni unlink 5 --blocked-by 3`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLink(cmd.Context(), args[0], func(client *uni.Client, id int) (*uni.Task, error) {
+			return client.Unlink(cmd.Context(), id, linkBlockedBy)
+		})
+	},
+}
+
+func runLink(ctx context.Context, arg string, apply func(client *uni.Client, id int) (*uni.Task, error)) error {
+	if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
+		return err
+	}
+
+	if len(linkBlockedBy) == 0 {
+		return fmt.Errorf("at least one blocker is required (use --blocked-by)")
+	}
+
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return fmt.Errorf("invalid task ID: %s", arg)
+	}
+
+	client, err := openClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	updatedTask, err := apply(client, id)
+	if err != nil {
+		return err
+	}
+
+	if GetOutputFormat() == "normal" {
+		if len(updatedTask.BlockedBy) == 0 {
+			fmt.Printf("Task #%d has no blockers (%s).\n", updatedTask.ID, updatedTask.Status)
+			return nil
+		}
+		fmt.Printf("Task #%d is blocked by %s (%s).\n", updatedTask.ID, formatTaskIDs(updatedTask.BlockedBy), updatedTask.Status)
+		return nil
+	}
+
+	return uni.WriteTask(os.Stdout, updatedTask, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()})
+}
+
+// formatTaskIDs renders IDs as "#1, #2"
+func formatTaskIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	linkCmd.Flags().IntSliceVar(&linkBlockedBy, "blocked-by", nil, "ID of a task blocking this one (repeatable)")
+	unlinkCmd.Flags().IntSliceVar(&linkBlockedBy, "blocked-by", nil, "ID of a blocker to remove (repeatable)")
+	rootCmd.AddCommand(linkCmd)
+	rootCmd.AddCommand(unlinkCmd)
+}
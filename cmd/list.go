@@ -1,32 +1,249 @@
 package cmd
 
 import (
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listTree    bool
+	listTags    []string
+	listSort    string
+	listColumns []string
+	listAll     bool
+	listStores  bool
+)
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:     "list",
+	Use:     "list [@view] [query]",
 	Aliases: []string{"l"},
 	Short:   "List all tasks",
-	Long:    `List all tasks with their current status.`,
+	Long: `List all tasks with their current status, optionally narrowed by a query.
+
+A query combines terms with and, or, not (or a leading -) and parentheses.
+A term is field<op>value or a bare word matching the name or description.
+
+  id, parent         numbers, with : = != < <= > >=
+  status             a workflow status, with : = !=
+  is                 left, active, closed, overdue or today
+  tag                a tag, with : = !=
+  priority           P0-P3 or none; priority<=P1 means P0 or P1
+  due                a due date (today, fri, 7d, 2024-06-01) or none
+  name, description  : for substring, = and != for an exact match
+  project            a project name, with : = !=
+
+--left and --closed are shorthands for is:left and is:closed.
+
+A first argument of @name runs the saved view with that name (see uni view);
+any query given after it narrows the view further.
+
+--all-projects lists the tasks of every project of the store (see uni project)
+with a project column in front. --all-stores does the same for every store uni
+has been used with (see uni stores), adding a store column; their tasks can be
+addressed as store#id, e.g. uni done api#12.`,
+	Example: `  uni list 'status:working and tag:backend'
+  uni list 'due<7d or priority:P0'
+  uni list 'is:left -tag:later (login or signup)'
+  uni list @standup tag:backend
+  uni list --all-projects 'is:left -project:infra'
+  uni list --all-stores --left
+  uni list --left -o markdown --columns id,status,name,updated_at`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
+		client, err := openClient(cmd.Context())
+		if err != nil {
 			return err
 		}
+		defer client.Close()
 
-		store, err := task.NewTaskStore()
-		if err != nil {
-			return err
+		name, view := "", uni.View{}
+		if len(args) > 0 && strings.HasPrefix(args[0], "@") {
+			name = strings.TrimPrefix(args[0], "@")
+			if view, err = lookupView(client, name); err != nil {
+				return err
+			}
+			args = args[1:]
 		}
 
-		tasks := store.ListTasksWithFilter(GetShowLeft(), GetShowClosed())
-		return output.FormatTasks(tasks, GetOutputFormat())
+		return runList(cmd, client, name, view, strings.Join(args, " "))
 	},
 }
 
+// runList lists the tasks matching a view and an extra query. Flags given on
+// the command line take precedence over the view's settings.
+func runList(cmd *cobra.Command, client *uni.Client, viewName string, view uni.View, extra string) error {
+	format := GetOutputFormat()
+	if view.Output != "" && !cmd.Flags().Changed("output") {
+		format = view.Output
+	}
+	if err := ValidateOutputFormat(format); err != nil {
+		return err
+	}
+
+	sortKey := listSort
+	if view.Sort != "" && !cmd.Flags().Changed("sort") {
+		sortKey = view.Sort
+	}
+
+	// The queries are checked here for their error positions, but run as one
+	// query by each store, which parses it with its own workflow
+	if err := checkQuery(client, view.Query); err != nil {
+		return fmt.Errorf("view %s: %v", viewName, err)
+	}
+	if err := checkQuery(client, extra); err != nil {
+		return err
+	}
+
+	listOpts := uni.ListOptions{
+		Query:  combineQueries(view.Query, extra),
+		Left:   GetShowLeft(),
+		Closed: GetShowClosed(),
+		Tags:   listTags,
+		Sort:   sortKey,
+	}
+	tasks, workflows, err := listTasks(cmd.Context(), client, listOpts)
+	if err != nil {
+		return err
+	}
+
+	opts := uni.FormatOptions{Columns: view.Columns, Workflow: client.Workflow(), StoreWorkflows: workflows}
+	if cmd.Flags().Changed("columns") {
+		opts.Columns = listColumns
+	}
+	if len(opts.Columns) == 0 && (listAll || listStores) {
+		opts.Columns = uni.DefaultColumns()
+		if listAll {
+			opts.Columns = append([]string{"project"}, opts.Columns...)
+		}
+		if listStores {
+			opts.Columns = append([]string{"store"}, opts.Columns...)
+		}
+	}
+	if listTree || view.Tree {
+		if listAll || listStores {
+			return fmt.Errorf("--tree cannot be combined with --all-projects or --all-stores")
+		}
+		return uni.WriteTaskTree(os.Stdout, tasks, format, opts)
+	}
+	return uni.WriteTasks(os.Stdout, tasks, format, opts)
+}
+
+// checkQuery checks a query against the workflow of client, or with
+// --all-stores only its syntax, as every store has its own workflow
+func checkQuery(client *uni.Client, input string) error {
+	if listStores {
+		return uni.CheckQuery(input)
+	}
+	_, err := uni.ParseQueryWorkflow(input, client.Workflow())
+	return err
+}
+
+// listTasks lists the tasks of client, or with --all-projects and --all-stores
+// those of every project and registered store, grouped by store and project
+// unless sorted by another key than id. It also returns the workflow of each
+// store listed, by store name, to render their tasks with. Stores whose
+// workflow lacks a status of the query are skipped with a warning, unless no
+// store accepts the query.
+func listTasks(ctx context.Context, client *uni.Client, opts uni.ListOptions) ([]uni.Task, map[string]*uni.Workflow, error) {
+	if !listAll && !listStores {
+		tasks, err := client.List(ctx, opts)
+		return tasks, nil, err
+	}
+
+	stores := []*uni.Client{client}
+	if listStores {
+		stores = openStores(ctx)
+		defer closeClients(stores)
+	}
+
+	tasks := []uni.Task{}
+	workflows := map[string]*uni.Workflow{}
+	var skipped []error
+	for _, store := range stores {
+		clients := []*uni.Client{store}
+		if listAll {
+			projects, err := openProjects(ctx, store)
+			if err != nil {
+				return nil, nil, err
+			}
+			defer closeClients(projects)
+			clients = projects
+		}
+
+		for _, c := range clients {
+			found, err := c.List(ctx, opts)
+			var queryErr *uni.QueryError
+			if listStores && errors.As(err, &queryErr) {
+				// The query names a status this store's workflow does not have
+				skipped = append(skipped, fmt.Errorf("store %s: %v", c.Location().Store, err))
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			tasks = append(tasks, found...)
+			workflows[c.Location().Store] = c.Workflow()
+		}
+	}
+	if len(skipped) > 0 && len(workflows) == 0 {
+		return nil, nil, fmt.Errorf("no store accepts the query: %v", errors.Join(skipped...))
+	}
+	for _, err := range skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %v\n", err)
+	}
+
+	if opts.Sort != uni.SortByID {
+		if err := uni.SortTasks(tasks, opts.Sort); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tasks, workflows, nil
+}
+
+// combineQueries joins two queries with and; either may be empty
+func combineQueries(a, b string) string {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return "(" + a + ") and (" + b + ")"
+}
+
+// lookupView returns the view with the given name from the config
+func lookupView(client *uni.Client, name string) (uni.View, error) {
+	view, ok := client.Views()[name]
+	if !ok {
+		return uni.View{}, fmt.Errorf("unknown view %q. Available views: %v", name, viewNames(client))
+	}
+	return view, nil
+}
+
+// viewNames returns the names of the configured views in alphabetical order
+func viewNames(client *uni.Client) []string {
+	names := make([]string, 0, len(client.Views()))
+	for name := range client.Views() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func init() {
+	listCmd.Flags().BoolVar(&listTree, "tree", false, "Show subtasks nested under their parents")
+	listCmd.Flags().StringArrayVarP(&listTags, "tag", "t", nil, "Show only tasks with this tag (repeatable, all must match)")
+	listCmd.Flags().StringVar(&listSort, "sort", uni.SortByID, "Sort by id, priority (then due date) or due (then priority)")
+	listCmd.Flags().StringSliceVar(&listColumns, "columns", nil, "Comma-separated columns for text, csv, tsv and markdown output, e.g. id,status,name,updated_at")
+	listCmd.Flags().BoolVar(&listAll, "all-projects", false, "List the tasks of all projects of the store")
+	listCmd.Flags().BoolVar(&listStores, "all-stores", false, "List the tasks of all stores uni has been used with")
 	rootCmd.AddCommand(listCmd)
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/mad01/uni/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+// mcpCmd represents the mcp command
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the tasks to AI assistants over the Model Context Protocol",
+	Long: `Speak the Model Context Protocol (MCP) over stdin and stdout so AI assistants
+can read and update the task list of the current directory. The tools are
+add_task, list_tasks (with the query language of uni list), get_task,
+set_task_status and edit_task; they return tasks as JSON.
+
+Register it with an MCP client as a stdio server running "uni mcp" in the
+repository, e.g.:
+
+  {"mcpServers": {"uni": {"command": "uni", "args": ["mcp"]}}}`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		return mcp.NewServer(client, gitHash).Serve(cmd.Context(), os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom  string
+	migrateTo    string
+	migrateForce bool
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy all tasks from one storage backend to another",
+	Long: `Copy all tasks from one storage backend to another in the same data directory.
+
+The source defaults to the current backend (--store, then config, then json).
+The source is left untouched; set "store" in the config file to switch to the new backend.`,
+	Example: `  uni migrate --to sqlite
+  uni migrate --from sqlite --to json --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateTo == "" {
+			return fmt.Errorf("target backend is required (use --to)")
+		}
+
+		var opts []uni.Option
+		if migrateFrom != "" {
+			opts = append(opts, uni.WithBackend(migrateFrom))
+		}
+		source, err := openClient(cmd.Context(), opts...)
+		if err != nil {
+			return err
+		}
+		defer source.Close()
+
+		n, err := source.Migrate(cmd.Context(), migrateTo, migrateForce)
+		var notEmpty *uni.StoreNotEmptyError
+		if errors.As(err, &notEmpty) {
+			return fmt.Errorf("%v (use --force to replace them)", err)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Migrated %d tasks from %s to %s.\n", n, source.Backend(), migrateTo)
+		fmt.Printf("Set \"store: %s\" in %s to use it by default.\n", migrateTo, source.ConfigPath())
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "Backend to copy tasks from (defaults to the current backend)")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Backend to copy tasks to (required)")
+	migrateCmd.Flags().BoolVar(&migrateForce, "force", false, "Replace tasks already present in the target backend")
+	migrateCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+// moveCmd represents the move command
+var moveCmd = &cobra.Command{
+	Use:     "move [id|range]... <status>",
+	Aliases: []string{"m"},
+	Short:   "Move tasks to any status of the workflow",
+	Long: `Move tasks to any status declared in the workflow.
+
+The transition must be allowed by the workflow, which can be customised in the
+config file. working, blocked, done and cancel are shortcuts for this command.
+
+` + batchHelp,
+	Example: `  uni move 3 review
+  uni move 3 qa --reason "ready for testing"
+  uni move 3 5 7-12 review
+  uni move --where 'tag:sprint-4 status:review' qa`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status := args[len(args)-1]
+		return runStatusChange(cmd.Context(), args[:len(args)-1], uni.TaskStatus(status), status)
+	},
+}
+
+func init() {
+	addReasonFlag(moveCmd)
+	addBatchFlags(moveCmd)
+	addCascadeFlag(moveCmd)
+	rootCmd.AddCommand(moveCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage the projects of the store",
+	Long: `A store can hold several projects. Each project has its own tasks and its
+own sequence of IDs, so infra #1 and the default project's #1 are different
+tasks. The workflow, views and other settings of the config file are shared.
+
+The tasks of the default project live in the data directory itself, those of
+the other projects in projects/<name> below it. Commands use the project given
+with -p, else the project setting of the config file, else the default project.`,
+	Example: `  uni project add infra
+  uni -p infra add -n "Rotate certificates"
+  uni -p infra list
+  uni list --all-projects project:infra`,
+}
+
+// projectAddCmd represents the project add command
+var projectAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := openClient(cmd.Context(), uni.WithProject(uni.DefaultProject))
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if err := client.AddProject(cmd.Context(), args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Created project %s\n", args[0])
+		return nil
+	},
+}
+
+// projectListCmd represents the project list command
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the projects and how many tasks they have",
+	Long:  `List the projects of the store. The selected project is marked with *.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client, err := openClient(ctx, uni.WithProject(uni.DefaultProject))
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		current := ""
+		if loc, err := uni.Locate(ctx, storeOptions()...); err == nil {
+			current = loc.Project
+		}
+
+		names, err := client.Projects(ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			project, err := client.OpenProject(ctx, name)
+			if err != nil {
+				return err
+			}
+			tasks, err := project.List(ctx, uni.ListOptions{})
+			left, _ := project.List(ctx, uni.ListOptions{Left: true})
+			project.Close()
+			if err != nil {
+				return err
+			}
+
+			marker := " "
+			if name == current {
+				marker = "*"
+			}
+			fmt.Printf("%s %-16s %d tasks, %d left\n", marker, name, len(tasks), len(left))
+		}
+		return nil
+	},
+}
+
+// openProjects opens every project of the store of client; the caller closes the clients
+func openProjects(ctx context.Context, client *uni.Client) ([]*uni.Client, error) {
+	names, err := client.Projects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []*uni.Client
+	for _, name := range names {
+		project, err := client.OpenProject(ctx, name)
+		if err != nil {
+			closeClients(clients)
+			return nil, err
+		}
+		clients = append(clients, project)
+	}
+	return clients, nil
+}
+
+func closeClients(clients []*uni.Client) {
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+func init() {
+	projectCmd.AddCommand(projectAddCmd)
+	projectCmd.AddCommand(projectListCmd)
+	rootCmd.AddCommand(projectCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportHTML  string
+	reportTitle string
+	reportSort  string
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report --html <file> [query]",
+	Short: "Write a self-contained HTML report of the tasks",
+	Long: `Write a single static HTML page with the tasks grouped by status, summary
+counts, collapsible descriptions and the status history of every task. The
+page embeds its CSS and loads nothing else, so it can be attached or mailed
+as is. An optional query (see uni list --help) narrows the tasks included.`,
+	Example: `  uni report --html retro.html
+  uni report --html - --title "Sprint 12" 'tag:backend' > sprint.html`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reportHTML == "" {
+			return fmt.Errorf("output file is required (use --html <file>, or - for stdout)")
+		}
+
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		tasks, err := client.List(cmd.Context(), uni.ListOptions{
+			Query:  strings.Join(args, " "),
+			Left:   GetShowLeft(),
+			Closed: GetShowClosed(),
+			Sort:   reportSort,
+		})
+		if err != nil {
+			return err
+		}
+
+		if reportHTML == "-" {
+			return uni.WriteHTMLReport(os.Stdout, reportTitle, tasks, time.Now(), client.Workflow())
+		}
+
+		f, err := os.Create(reportHTML)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %v", err)
+		}
+		if err := uni.WriteHTMLReport(f, reportTitle, tasks, time.Now(), client.Workflow()); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+
+		fmt.Printf("Report with %d tasks written to %s.\n", len(tasks), reportHTML)
+		return nil
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportHTML, "html", "", "File to write the HTML report to (- for stdout)")
+	reportCmd.Flags().StringVar(&reportTitle, "title", "Task report", "Title of the report")
+	reportCmd.Flags().StringVar(&reportSort, "sort", uni.SortByID, "Order of the tasks within each status: id, priority or due")
+	rootCmd.AddCommand(reportCmd)
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
@@ -11,13 +14,23 @@ var (
 	outputFormat string
 	showLeft     bool
 	showClosed   bool
+	storeBackend string
+	dataDirFlag  string
+	projectFlag  string
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "uni",
 	Short: "A minimal task management CLI",
-	Long:  `uni is a minimal task management CLI that stores tasks in JSON files.`,
+	Long: `uni is a minimal task management CLI.
+
+Tasks are kept in the .uni directory of the git repository you are in, found
+by walking up from the working directory, or else in ~/.uni. They are stored
+as JSON files by default, or in a SQLite database or an append-only event log
+(see --store). A store can hold several projects with their own tasks (see
+uni project), and every store uni has been used with is remembered, so tasks
+of other repositories can be listed and addressed as name#id (see uni stores).`,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -29,9 +42,12 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "normal", "Output format (normal, text, json, yaml)")
-	rootCmd.PersistentFlags().BoolVar(&showLeft, "left", false, "Show only left (open, working, blocked) tasks")
-	rootCmd.PersistentFlags().BoolVar(&showClosed, "closed", false, "Show only closed (done, cancelled) tasks")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "normal", "Output format (normal, text, json, yaml, csv, tsv, markdown, ics, template=<template>, template-file=<path>)")
+	rootCmd.PersistentFlags().BoolVar(&showLeft, "left", false, "Show only left tasks (active statuses, by default open, working, blocked); same as the query is:left")
+	rootCmd.PersistentFlags().BoolVar(&showClosed, "closed", false, "Show only closed tasks (closed statuses, by default done, cancel); same as the query is:closed")
+	rootCmd.PersistentFlags().StringVar(&storeBackend, "store", "", "Storage backend (json, sqlite, events, memory); overrides the store setting in config")
+	rootCmd.PersistentFlags().StringVarP(&projectFlag, "project", "p", "", "Project to use (see uni project); overrides the project setting in config")
+	rootCmd.PersistentFlags().StringVar(&dataDirFlag, "dir", "", "Data directory to use instead of searching for .uni; overrides UNI_DIR")
 }
 
 // GetOutputFormat returns the current output format
@@ -51,11 +67,54 @@ func GetShowClosed() bool {
 
 // ValidateOutputFormat validates the output format
 func ValidateOutputFormat(format string) error {
-	validFormats := []string{"normal", "text", "json", "yaml"}
-	for _, valid := range validFormats {
-		if format == valid {
-			return nil
-		}
+	return uni.ValidateFormat(format)
+}
+
+// openClient opens the store selected by --dir, --store and --project, see storeOptions
+func openClient(ctx context.Context, extra ...uni.Option) (*uni.Client, error) {
+	client, err := uni.Open(ctx, storeOptions(extra...)...)
+	switch {
+	case errors.Is(err, uni.ErrProjectNotFound):
+		return nil, fmt.Errorf("%v (see uni project list)", err)
+	case errors.Is(err, uni.ErrStoreNotFound):
+		return nil, fmt.Errorf("%v (see uni stores)", err)
+	case err != nil:
+		return nil, err
+	}
+
+	// Remember the store for uni list --all-stores and name#id references;
+	// a registry that cannot be written must not stop the command
+	client.Register(ctx)
+	return client, nil
+}
+
+// openTaskRef opens the store a task reference such as 12 or api#12 points to
+func openTaskRef(ctx context.Context, arg string) (*uni.Client, uni.TaskRef, error) {
+	ref, err := uni.ParseTaskRef(arg)
+	if err != nil {
+		return nil, ref, err
+	}
+
+	var extra []uni.Option
+	if ref.Store != "" {
+		extra = append(extra, uni.WithStore(ref.Store))
+	}
+	client, err := openClient(ctx, extra...)
+	return client, ref, err
+}
+
+// storeOptions returns the options selecting the store given by --dir, --store and --project.
+// Options in extra take precedence.
+func storeOptions(extra ...uni.Option) []uni.Option {
+	var opts []uni.Option
+	if dataDirFlag != "" {
+		opts = append(opts, uni.WithDataDir(dataDirFlag))
+	}
+	if storeBackend != "" {
+		opts = append(opts, uni.WithBackend(storeBackend))
+	}
+	if projectFlag != "" {
+		opts = append(opts, uni.WithProject(projectFlag))
 	}
-	return fmt.Errorf("invalid output format: %s. Valid formats: %v", format, validFormats)
+	return append(opts, extra...)
 }
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var searchLimit int
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <terms>...",
+	Short: "Search task names, descriptions and notes",
+	Long: `Search the names, descriptions and notes (status change reasons) of all tasks
+and list the matches best first. Every term must match. Quote a "phrase" to
+match words in order, and end a term with * to match any word starting with it.
+
+Each search reads and indexes all tasks; nothing is kept on disk.`,
+	Example: `  uni search redis
+  uni search '"connection pool"' timeout
+  uni search deploy* --left`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
+			return err
+		}
+
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		results, err := client.Search(cmd.Context(), strings.Join(args, " "), uni.ListOptions{
+			Left:   GetShowLeft(),
+			Closed: GetShowClosed(),
+		})
+		if err != nil {
+			return err
+		}
+		if searchLimit > 0 && len(results) > searchLimit {
+			results = results[:searchLimit]
+		}
+		return uni.WriteSearchResults(os.Stdout, results, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()})
+	},
+}
+
+func init() {
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Show at most this many results (0 for all)")
+	rootCmd.AddCommand(searchCmd)
+}
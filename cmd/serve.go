@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mad01/uni/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the tasks over a JSON REST API",
+	Long: `Serve the tasks over a JSON REST API:
+
+  GET    /tasks              list tasks (q, left, closed, status, tag, parent, blocked_by, sort)
+  POST   /tasks              create a task
+  GET    /tasks/{id}         get a task
+  PATCH  /tasks/{id}         change name, description, tags, priority or due_at
+  DELETE /tasks/{id}         delete a task
+  POST   /tasks/{id}/status  change the status: {"status": "done", "reason": "..."}
+  GET    /openapi.json       OpenAPI document
+
+Single-task responses carry an ETag; send it as If-Match to refuse a write
+when the task changed in the meantime (412 Precondition Failed). There is no
+authentication, so the server listens on localhost unless --addr says otherwise;
+--addr :8080 listens on all interfaces. Request bodies must be sent as
+application/json.`,
+	Example: `  uni serve
+  uni serve --addr :8080
+  curl localhost:8080/tasks?q=tag:backend`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		server := &http.Server{
+			Addr:              serveAddr,
+			Handler:           api.NewServer(client),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdown)
+		}()
+
+		ln, err := net.Listen("tcp", serveAddr)
+		if err != nil {
+			return fmt.Errorf("failed to serve: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Serving tasks on http://%s\n", ln.Addr())
+		if addr, ok := ln.Addr().(*net.TCPAddr); ok && addr.IP.IsUnspecified() {
+			fmt.Fprintf(os.Stderr, "Warning: listening on all interfaces; anyone who can reach this machine can read and change the tasks\n")
+		}
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve: %v", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8080", "Address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
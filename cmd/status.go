@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// statusReason is the optional reason recorded with a status change
+	statusReason string
+	// statusCascade closes open subtasks together with their parent
+	statusCascade bool
+	// statusWhere selects the tasks to change with a query
+	statusWhere string
+	// statusContinue applies a batch to the tasks it can instead of failing it
+	statusContinue bool
+)
+
+// addReasonFlag registers the --reason flag on a status command
+func addReasonFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&statusReason, "reason", "r", "", "Reason for the status change, kept in the task history")
+}
+
+// addCascadeFlag registers the --cascade flag on a status command that can close tasks
+func addCascadeFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&statusCascade, "cascade", false, "Also apply a closing status to all open subtasks")
+}
+
+// batchHelp explains how the status commands select tasks
+const batchHelp = `Tasks are given as IDs, ranges like 7-12 and the query of --where, or
+qualified with a registered store, as in api#12. All of them are changed in
+one transaction: if any is missing or cannot make the transition, none is
+changed, unless --continue-on-error is given, which still exits non-zero
+if any task failed. With -o json or yaml the result is reported for each ID.
+`
+
+// addBatchFlags registers the flags selecting several tasks on a status command
+func addBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&statusWhere, "where", "", "Also change the tasks matching this query, e.g. 'tag:sprint-4'")
+	cmd.Flags().BoolVar(&statusContinue, "continue-on-error", false, "Change the tasks that can be changed instead of failing the whole batch")
+}
+
+// runStatusChange moves the tasks given by refs and --where to status, validated
+// against the workflow, in one transaction, and reports each as "marked as <label>".
+// A ref is an ID, a range like 7-12, or either qualified with a store, as in api#12.
+func runStatusChange(ctx context.Context, refs []string, status uni.TaskStatus, label string) error {
+	if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
+		return err
+	}
+	if len(refs) == 0 && statusWhere == "" {
+		return fmt.Errorf("give at least one task ID or --where")
+	}
+
+	store, ids, err := parseTaskIDs(refs)
+	if err != nil {
+		return err
+	}
+
+	var extra []uni.Option
+	if store != "" {
+		extra = append(extra, uni.WithStore(store))
+	}
+	client, err := openClient(ctx, extra...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	batch := uni.Batch{IDs: ids, ContinueOnError: statusContinue}
+	if statusWhere != "" {
+		batch.Where = &uni.ListOptions{Query: statusWhere}
+	}
+	results, err := client.SetStatuses(ctx, batch, uni.StatusChange{
+		Status:  status,
+		Reason:  statusReason,
+		Cascade: statusCascade,
+	})
+	if err != nil {
+		return err
+	}
+
+	if client.Workflow().IsClosed(status) && !statusCascade {
+		for _, r := range results {
+			if r.OK {
+				defer warnOpenSubtasks(ctx, client, r.ID)
+			}
+		}
+	}
+
+	if GetOutputFormat() != "normal" {
+		if err := uni.WriteStatusResults(os.Stdout, results, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()}); err != nil {
+			return err
+		}
+		return batchError(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No tasks matched.")
+	}
+	for _, r := range results {
+		ref := uni.TaskRef{Store: store, ID: r.ID}
+		switch {
+		case r.OK && r.Task.Status == uni.StatusBlocked && status != uni.StatusBlocked:
+			fmt.Printf("Task %s stays blocked until its blockers are closed (%s).\n", ref, formatTaskIDs(r.Task.BlockedBy))
+		case r.OK:
+			fmt.Printf("Task %s marked as %s.\n", ref, label)
+		default:
+			fmt.Fprintf(os.Stderr, "Task %s not changed: %s\n", ref, r.Error)
+		}
+	}
+	return batchError(results)
+}
+
+// batchError returns an error if any task of a batch could not be changed
+func batchError(results []uni.StatusResult) error {
+	failed := 0
+	for _, r := range results {
+		if !r.OK {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tasks could not be changed", failed, len(results))
+	}
+	return nil
+}
+
+// maxRangeSize is the largest number of IDs a range such as 7-12 may expand to
+const maxRangeSize = 10000
+
+// parseTaskIDs expands task IDs and ranges such as 3, 7-12 or api#7-12 into IDs.
+// All of them must be in the same store, whose name is returned. A range
+// spans at most maxRangeSize IDs.
+func parseTaskIDs(args []string) (string, []int, error) {
+	var store string
+	var ids []int
+	for i, arg := range args {
+		prefix, span := "", arg
+		if name, rest, ok := strings.Cut(arg, "#"); ok {
+			prefix, span = name+"#", rest
+		}
+
+		lo, hi, isRange := strings.Cut(span, "-")
+		first, err := uni.ParseTaskRef(prefix + lo)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid task ID: %s", arg)
+		}
+		last := first.ID
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first.ID || last-first.ID >= maxRangeSize {
+				return "", nil, fmt.Errorf("invalid task ID range: %s", arg)
+			}
+		}
+
+		if i > 0 && first.Store != store {
+			return "", nil, fmt.Errorf("task IDs must all be in the same store: %s", arg)
+		}
+		store = first.Store
+		for id := first.ID; id <= last; id++ {
+			ids = append(ids, id)
+		}
+	}
+	return store, ids, nil
+}
+
+// warnOpenSubtasks prints a warning to stderr if a closed task still has open subtasks
+func warnOpenSubtasks(ctx context.Context, client *uni.Client, id int) {
+	children, err := client.OpenSubtasks(ctx, id)
+	if err != nil || len(children) == 0 {
+		return
+	}
+
+	ids := make([]int, len(children))
+	for i, child := range children {
+		ids[i] = child.ID
+	}
+	fmt.Fprintf(os.Stderr, "Warning: task #%d still has open subtasks: %s (use --cascade to close them too)\n", id, formatTaskIDs(ids))
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var (
+	storesPrune  bool
+	storesForget []string
+)
+
+// storesCmd represents the stores command
+var storesCmd = &cobra.Command{
+	Use:   "stores",
+	Short: "List the stores uni has been used with",
+	Long: `List the data directories uni has been used with. Every command that opens a
+store records it in ~/.uni/stores.yaml under the name of its git repository
+(or of the directory holding .uni), adding -2, -3, ... when a name is taken.
+
+The name addresses the store from anywhere: uni list --all-stores lists the
+tasks of all stores, and an ID like api#12 refers to task 12 of store api.`,
+	Example: `  uni stores
+  uni stores --prune
+  uni get api#12`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		for _, name := range storesForget {
+			if err := uni.Unregister(ctx, name); err != nil {
+				return err
+			}
+			fmt.Printf("Forgot store %s\n", name)
+		}
+
+		stores, err := uni.Stores(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range stores {
+			_, statErr := os.Stat(s.Dir)
+			switch {
+			case statErr != nil && storesPrune:
+				if err := uni.Unregister(ctx, s.Name); err != nil {
+					return err
+				}
+				fmt.Printf("Pruned store %s (%s no longer exists)\n", s.Name, s.Dir)
+			case statErr != nil:
+				fmt.Printf("%-16s %s (missing)\n", s.Name, s.Dir)
+			default:
+				fmt.Printf("%-16s %s\n", s.Name, s.Dir)
+			}
+		}
+		return nil
+	},
+}
+
+// openStores opens every registered store, skipping with a warning those that
+// are gone or cannot be opened; the caller closes the clients
+func openStores(ctx context.Context) []*uni.Client {
+	stores, err := uni.Stores(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+
+	var clients []*uni.Client
+	for _, s := range stores {
+		if _, err := os.Stat(s.Dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping store %s: %s no longer exists (see uni stores --prune)\n", s.Name, s.Dir)
+			continue
+		}
+		client, err := uni.Open(ctx, uni.WithStore(s.Name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping store %s: %v\n", s.Name, err)
+			continue
+		}
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+func init() {
+	storesCmd.Flags().BoolVar(&storesPrune, "prune", false, "Forget stores whose directory no longer exists")
+	storesCmd.Flags().StringArrayVar(&storesForget, "forget", nil, "Forget the store with this name (repeatable); its tasks are kept")
+	rootCmd.AddCommand(storesCmd)
+}
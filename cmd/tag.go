@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag <id> [+tag|-tag]...",
+	Short: "Add or remove tags on a task",
+	Long: `Add or remove tags on a task. Prefix a tag with + (or nothing) to add it
+and with - to remove it, so -p removes the tag p. Flags given after the ID
+must be written out, as in --output json; shorthands such as -o only work
+before the ID.`,
+	Example: `  uni tag 5 +backend +urgent
+  uni tag 5 -urgent
+  uni tag 5 +api -backend --output json
+  uni tag -o json 5 -p`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagArgs, flagArgs := splitTagArgs(cmd, args[1:])
+		if err := cmd.Flags().Parse(flagArgs); err != nil {
+			return err
+		}
+
+		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
+			return err
+		}
+
+		var add, remove []string
+		for _, arg := range tagArgs {
+			switch {
+			case strings.HasPrefix(arg, "-"):
+				remove = append(remove, strings.TrimPrefix(arg, "-"))
+			default:
+				add = append(add, strings.TrimPrefix(arg, "+"))
+			}
+		}
+
+		client, ref, err := openTaskRef(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		updatedTask, err := client.Tag(cmd.Context(), ref.ID, add, remove)
+		if err != nil {
+			return err
+		}
+
+		if GetOutputFormat() == "normal" {
+			if len(updatedTask.Tags) == 0 {
+				fmt.Printf("Task %s has no tags.\n", ref)
+				return nil
+			}
+			fmt.Printf("Task %s tagged: %s\n", ref, strings.Join(updatedTask.Tags, ", "))
+			return nil
+		}
+
+		return uni.WriteTask(os.Stdout, updatedTask, GetOutputFormat(), uni.FormatOptions{Workflow: client.Workflow()})
+	},
+}
+
+// splitTagArgs separates the +tag/-tag arguments from flags given after the task ID.
+// Only arguments starting with -- are flags; -x is always the tag x to remove.
+func splitTagArgs(cmd *cobra.Command, args []string) (tags, flags []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			tags = append(tags, arg)
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(strings.SplitN(arg[2:], "=", 2)[0])
+		flags = append(flags, arg)
+		if flag != nil && flag.NoOptDefVal == "" && !strings.Contains(arg, "=") && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return tags, flags
+}
+
+func init() {
+	// Stop flag parsing at the task ID so "-tag" arguments are not taken as flags
+	tagCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(tagCmd)
+}
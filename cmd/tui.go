@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/mad01/uni/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui [query]",
+	Short: "Open an interactive kanban board",
+	Long: `Open a full-screen board with one column per workflow status. Move between
+cards with the arrow keys (or h/j/k/l), move the selected card to the previous
+or next status with shift+arrow (or H/L), edit its name with e and its
+description with d, and narrow the board with / and a query (see uni list
+--help). The board reloads when another uni process changes the tasks.`,
+	Example: `  uni tui
+  uni tui 'tag:backend -is:closed'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		board, err := tui.NewBoard(client, client.Location().ProjectDir, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		return tui.Run(board)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
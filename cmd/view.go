@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// viewCmd represents the view command
+var viewCmd = &cobra.Command{
+	Use:   "view [name] [query]",
+	Short: "Run a saved view, or list the saved views",
+	Long: `Run a view saved in the config file: a named query together with its sort
+order, output format and columns. Without a name, list the available views.
+
+Views are read from .uni/config in the repository and from ~/.uni/config;
+a view in the repository config replaces a global view with the same name.
+
+  views:
+    standup:
+      description: What I'm on and what's due
+      query: is:left and (status:working or due<=today)
+      sort: priority
+      output: text
+      columns: [id, status, priority, due, name]
+
+"uni view standup" is the same as "uni list @standup".`,
+	Example: `  uni view
+  uni view standup
+  uni view standup tag:backend -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := openClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if len(args) == 0 {
+			if len(client.Views()) == 0 {
+				fmt.Println("No views configured.")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VIEW\tQUERY\tDESCRIPTION")
+			for _, name := range viewNames(client) {
+				view := client.Views()[name]
+				fmt.Fprintf(w, "%s\t%s\t%s\n", name, view.Query, view.Description)
+			}
+			return w.Flush()
+		}
+
+		view, err := lookupView(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		return runList(cmd, client, args[0], view, strings.Join(args[1:], " "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mad01/uni/pkg/uni"
+	"github.com/spf13/cobra"
+)
+
+var wherePath bool
+
+// whereCmd represents the where command
+var whereCmd = &cobra.Command{
+	Use:   "where",
+	Short: "Show which task store is used and why",
+	Long: `Show the data directory uni uses here, how it was chosen, its backend and
+its config file.
+
+The data directory is the first of:
+
+  1. the --dir flag
+  2. the UNI_DIR environment variable
+  3. the nearest .uni directory in the current directory or above it, up to
+     the root of the git repository (worktrees without a .uni of their own
+     use the one of their main checkout)
+  4. ~/.uni
+
+With a project selected (see uni project) its name and directory are shown too,
+and so is the name of the store in the registry (see uni stores).`,
+	Example: `  uni where
+  cd "$(dirname "$(uni where --path)")"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loc, err := uni.Locate(cmd.Context(), storeOptions()...)
+		if err != nil {
+			return err
+		}
+
+		if wherePath {
+			fmt.Println(loc.Dir)
+			return nil
+		}
+
+		fmt.Printf("Store:   %s (%s)\n", loc.Dir, loc.Backend)
+		fmt.Printf("Found:   %s\n", describeSource(loc))
+		if stores, err := uni.Stores(cmd.Context()); err == nil {
+			for _, s := range stores {
+				if s.Dir == loc.Dir {
+					fmt.Printf("Name:    %s (address its tasks as %s#<id>)\n", s.Name, s.Name)
+				}
+			}
+		}
+		if loc.Project != uni.DefaultProject {
+			fmt.Printf("Project: %s (%s)\n", loc.Project, loc.ProjectDir)
+		}
+		config := loc.ConfigFile
+		if _, err := os.Stat(config); err != nil {
+			config += " (not present)"
+		}
+		fmt.Printf("Config:  %s\n", config)
+		return nil
+	},
+}
+
+// describeSource explains how the data directory of loc was chosen
+func describeSource(loc *uni.Location) string {
+	switch loc.Source {
+	case uni.SourceOption:
+		return "--dir flag"
+	case uni.SourceRegistry:
+		return fmt.Sprintf("registered store %s", loc.Store)
+	case uni.SourceEnv:
+		return "UNI_DIR environment variable"
+	case uni.SourceDirectory:
+		if loc.Root != "" {
+			return fmt.Sprintf(".uni directory of repository %s", loc.Root)
+		}
+		return fmt.Sprintf(".uni directory in %s", filepath.Dir(loc.Dir))
+	case uni.SourceWorktree:
+		return fmt.Sprintf(".uni directory of the main checkout of worktree %s", loc.Root)
+	default:
+		if loc.Root != "" {
+			return fmt.Sprintf("global store; repository %s has no .uni directory", loc.Root)
+		}
+		return "global store; no .uni directory or git repository found"
+	}
+}
+
+func init() {
+	whereCmd.Flags().BoolVar(&wherePath, "path", false, "Print only the data directory")
+	rootCmd.AddCommand(whereCmd)
+}
@@ -1,50 +1,30 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-
-	"github.com/mad01/uni/internal/output"
-	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
 	"github.com/spf13/cobra"
 )
 
 // workingCmd represents the working command
 var workingCmd = &cobra.Command{
-	Use:     "working <id>",
+	Use:     "working [id|range]...",
 	Aliases: []string{"w"},
-	Short:   "Mark a task as working",
-	Long:    `Mark a task as working by providing its ID.`,
-	Args:    cobra.ExactArgs(1),
+	Short:   "Mark tasks as working",
+	Long: `Mark tasks as working by providing their IDs or a query.
+
+` + batchHelp + `
+This is a shortcut for "uni move <id>... working".`,
+	Example: `  uni working 3
+  uni working 3 5 7-12
+  uni working --where 'tag:sprint-4'`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := ValidateOutputFormat(GetOutputFormat()); err != nil {
-			return err
-		}
-
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid task ID: %s", args[0])
-		}
-
-		store, err := task.NewTaskStore()
-		if err != nil {
-			return err
-		}
-
-		updatedTask, err := store.UpdateTaskStatus(id, task.StatusWorking)
-		if err != nil {
-			return err
-		}
-
-		if GetOutputFormat() == "normal" {
-			fmt.Printf("Task #%d marked as working.\n", updatedTask.ID)
-			return nil
-		}
-
-		return output.FormatTask(updatedTask, GetOutputFormat())
+		return runStatusChange(cmd.Context(), args, uni.StatusWorking, "working")
 	},
 }
 
 func init() {
+	addReasonFlag(workingCmd)
+	addBatchFlags(workingCmd)
 	rootCmd.AddCommand(workingCmd)
 }
@@ -0,0 +1,474 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
+)
+
+// maxBodySize caps the size of request bodies
+const maxBodySize = 1 << 20
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// Server exposes the tasks of a store as a JSON REST API:
+//
+//	GET    /tasks              list tasks, filtered by query parameters
+//	POST   /tasks              create a task
+//	GET    /tasks/{id}         get a task
+//	PATCH  /tasks/{id}         change name, description, tags, priority or due date
+//	DELETE /tasks/{id}         delete a task
+//	POST   /tasks/{id}/status  change the status of a task
+//	GET    /openapi.json       the OpenAPI document describing the above
+//
+// Every response carrying a single task has an ETag derived from its UpdatedAt.
+// Writes honor If-Match and fail with 412 when the task changed in the meantime.
+type Server struct {
+	client *uni.Client
+}
+
+// NewServer returns an HTTP handler serving the tasks of client
+func NewServer(client *uni.Client) *Server {
+	return &Server{client: client}
+}
+
+// ServeHTTP routes a request to its handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "openapi.json":
+		if allow(w, r, http.MethodGet) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(openAPIDocument)
+		}
+	case len(parts) == 1 && parts[0] == "tasks":
+		if allow(w, r, http.MethodGet, http.MethodPost) {
+			if r.Method == http.MethodGet {
+				s.listTasks(w, r)
+			} else {
+				s.createTask(w, r)
+			}
+		}
+	case len(parts) >= 2 && len(parts) <= 3 && parts[0] == "tasks":
+		id, err := strconv.Atoi(parts[1])
+		if err != nil || id <= 0 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("invalid task ID: %s", parts[1]))
+			return
+		}
+
+		if len(parts) == 3 {
+			if parts[2] != "status" {
+				writeError(w, http.StatusNotFound, fmt.Errorf("not found: %s", r.URL.Path))
+			} else if allow(w, r, http.MethodPost) {
+				s.changeStatus(w, r, id)
+			}
+			return
+		}
+
+		if allow(w, r, http.MethodGet, http.MethodPatch, http.MethodDelete) {
+			switch r.Method {
+			case http.MethodGet:
+				s.getTask(w, r, id)
+			case http.MethodPatch:
+				s.patchTask(w, r, id)
+			case http.MethodDelete:
+				s.deleteTask(w, r, id)
+			}
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found: %s", r.URL.Path))
+	}
+}
+
+// allow reports whether the request uses one of methods, answering 405 otherwise
+func allow(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+// listTasks answers GET /tasks. It accepts the filters of uni list: q (a query),
+// left, closed, tag and sort, plus status, parent and blocked_by.
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListParams(r.URL.Query(), s.client.Workflow())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := s.client.List(r.Context(), opts)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func parseListParams(params url.Values, workflow *uni.Workflow) (uni.ListOptions, error) {
+	opts := uni.ListOptions{
+		Query:  params.Get("q"),
+		Left:   params.Get("left") == "true",
+		Closed: params.Get("closed") == "true",
+		Tags:   splitParam(params["tag"]),
+		Sort:   params.Get("sort"),
+	}
+
+	if _, err := uni.ParseQueryWorkflow(opts.Query, workflow); err != nil {
+		return opts, err
+	}
+	if err := uni.SortTasks(nil, opts.Sort); err != nil {
+		return opts, err
+	}
+
+	for _, status := range splitParam(params["status"]) {
+		if _, ok := workflow.Status(task.TaskStatus(status)); !ok {
+			return opts, fmt.Errorf("unknown status %s. Valid statuses: %v", status, workflow.StatusNames())
+		}
+		opts.Statuses = append(opts.Statuses, task.TaskStatus(status))
+	}
+
+	for name, target := range map[string]*int{"parent": &opts.Parent, "blocked_by": &opts.BlockedBy} {
+		if value := params.Get(name); value != "" {
+			var err error
+			if *target, err = strconv.Atoi(value); err != nil {
+				return opts, fmt.Errorf("invalid %s: %s", name, value)
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// splitParam accepts both repeated parameters and comma-separated values
+func splitParam(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// createRequest is the body of POST /tasks
+type createRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Parent      int      `json:"parent"`
+	Tags        []string `json:"tags"`
+	Priority    string   `json:"priority"`
+	// DueAt accepts everything --due does: 2024-05-31, today, friday, +3d, ...
+	DueAt string `json:"due_at"`
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	spec := task.TaskSpec{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Parent:      req.Parent,
+	}
+	if spec.Name == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("task name cannot be empty"))
+		return
+	}
+
+	var err error
+	if spec.Tags, err = task.NormalizeTags(req.Tags); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if spec.Priority, err = task.ParsePriority(req.Priority); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if spec.DueAt, err = parseDue(req.DueAt); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := s.client.Create(r.Context(), spec)
+	if errors.Is(err, task.ErrNotFound) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", created.ID))
+	writeTask(w, http.StatusCreated, created)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request, id int) {
+	t, err := s.client.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, t)
+}
+
+// patchTask applies the fields present in the body; "due_at": null clears the due date
+func (s *Server) patchTask(w http.ResponseWriter, r *http.Request, id int) {
+	var fields map[string]json.RawMessage
+	if err := decodeBody(w, r, &fields); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	current, err := s.client.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if _, ok := checkIfMatch(w, r, current); !ok {
+		return
+	}
+
+	updated := *current
+	if err := applyPatch(&updated, fields); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.client.Update(r.Context(), &updated); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, &updated)
+}
+
+// applyPatch sets the fields of t given in a PATCH body
+func applyPatch(t *task.Task, fields map[string]json.RawMessage) error {
+	for name, raw := range fields {
+		var err error
+		switch name {
+		case "name":
+			err = json.Unmarshal(raw, &t.Name)
+			t.Name = strings.TrimSpace(t.Name)
+			if err == nil && t.Name == "" {
+				err = fmt.Errorf("task name cannot be empty")
+			}
+		case "description":
+			err = json.Unmarshal(raw, &t.Description)
+		case "tags":
+			var tags []string
+			if err = json.Unmarshal(raw, &tags); err == nil {
+				t.Tags, err = task.NormalizeTags(tags)
+			}
+		case "priority":
+			var priority *string
+			if err = json.Unmarshal(raw, &priority); err == nil {
+				t.Priority = task.PriorityNone
+				if priority != nil {
+					t.Priority, err = task.ParsePriority(*priority)
+				}
+			}
+		case "due_at":
+			var due *string
+			if err = json.Unmarshal(raw, &due); err == nil {
+				t.DueAt = nil
+				if due != nil {
+					t.DueAt, err = parseDue(*due)
+				}
+			}
+		default:
+			err = fmt.Errorf("field cannot be changed")
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request, id int) {
+	current, err := s.client.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	version, ok := checkIfMatch(w, r, current)
+	if !ok {
+		return
+	}
+
+	if err := s.client.Delete(r.Context(), id, version); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// statusRequest is the body of POST /tasks/{id}/status
+type statusRequest struct {
+	Status  task.TaskStatus `json:"status"`
+	Reason  string          `json:"reason"`
+	Cascade bool            `json:"cascade"`
+}
+
+func (s *Server) changeStatus(w http.ResponseWriter, r *http.Request, id int) {
+	var req statusRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	workflow := s.client.Workflow()
+	if _, ok := workflow.Status(req.Status); !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown status %q. Valid statuses: %v", req.Status, workflow.StatusNames()))
+		return
+	}
+
+	current, err := s.client.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	version, ok := checkIfMatch(w, r, current)
+	if !ok {
+		return
+	}
+
+	changed, err := s.client.SetStatus(r.Context(), id, task.StatusChange{
+		Status:    req.Status,
+		Reason:    req.Reason,
+		Cascade:   req.Cascade,
+		UpdatedAt: version,
+	})
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, changed)
+}
+
+// ETag returns the entity tag of a task, which changes with every update
+func ETag(t *task.Task) string {
+	return fmt.Sprintf(`"%d-%d"`, t.ID, t.UpdatedAt.UnixNano())
+}
+
+// checkIfMatch compares the If-Match header with the current task, answering 412 on a
+// mismatch. It returns the version writes must still find, or the zero time when
+// the request carries no If-Match.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, current *task.Task) (time.Time, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return time.Time{}, true
+	}
+
+	etag := ETag(current)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return current.UpdatedAt, true
+		}
+	}
+
+	w.Header().Set("ETag", etag)
+	writeError(w, http.StatusPreconditionFailed, fmt.Errorf("task #%d was modified since it was read", current.ID))
+	return time.Time{}, false
+}
+
+// parseDue accepts the same due dates as uni add --due; an empty string means none
+func parseDue(input string) (*time.Time, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	due, err := task.ParseDue(input, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
+
+// errUnsupportedMediaType is returned by decodeBody for a body that is not declared as JSON
+var errUnsupportedMediaType = errors.New("request body must be application/json")
+
+// decodeBody reads a JSON request body, rejecting unknown fields. The body must
+// be declared as application/json, which a browser cannot send cross-site
+// without a preflight.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return errUnsupportedMediaType
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("request body is empty")
+		}
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeTask(w http.ResponseWriter, status int, t *task.Task) {
+	w.Header().Set("ETag", ETag(t))
+	writeJSON(w, status, t)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeBodyError reports a request body decodeBody rejected
+func writeBodyError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errUnsupportedMediaType) {
+		status = http.StatusUnsupportedMediaType
+	}
+	writeError(w, status, err)
+}
+
+// writeStoreError maps the errors of the task store to HTTP statuses
+func writeStoreError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, task.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, task.ErrConflict):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, task.ErrInvalidTransition), errors.Is(err, task.ErrInUse), errors.Is(err, task.ErrDependencyCycle):
+		status = http.StatusConflict
+	}
+	writeError(w, status, err)
+}
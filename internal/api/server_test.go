@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *uni.Client) {
+	t.Helper()
+	client, err := uni.Open(context.Background(), uni.WithDataDir(t.TempDir()), uni.WithBackend("memory"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	server := httptest.NewServer(NewServer(client))
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+	return server, client
+}
+
+func do(t *testing.T, method, url, body string, header map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decode(t *testing.T, resp *http.Response, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+}
+
+func TestServer_CreateGetList(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp := do(t, "POST", server.URL+"/tasks", `{"name": "Deploy", "tags": ["Ops"], "priority": "p1", "due_at": "2024-05-31"}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	var created task.Task
+	decode(t, resp, &created)
+	if resp.Header.Get("Location") != "/tasks/1" || resp.Header.Get("ETag") != ETag(&created) {
+		t.Errorf("Unexpected headers: %v", resp.Header)
+	}
+	if created.Priority != task.PriorityP1 || created.Tags[0] != "ops" || created.DueAt == nil {
+		t.Errorf("Expected fields to be parsed, got %+v", created)
+	}
+	do(t, "POST", server.URL+"/tasks", `{"name": "Write docs"}`, nil)
+
+	resp = do(t, "GET", server.URL+"/tasks/1", "", nil)
+	var got task.Task
+	decode(t, resp, &got)
+	if resp.StatusCode != http.StatusOK || got.Name != "Deploy" || resp.Header.Get("ETag") != ETag(&created) {
+		t.Errorf("Expected to get the created task, got %d %+v", resp.StatusCode, got)
+	}
+
+	for query, want := range map[string]int{
+		"":                         2,
+		"?tag=ops":                 1,
+		"?q=docs":                  1,
+		"?status=open,working":     2,
+		"?closed=true":             0,
+		"?q=priority:P1&left=true": 1,
+	} {
+		var tasks []task.Task
+		resp := do(t, "GET", server.URL+"/tasks"+query, "", nil)
+		decode(t, resp, &tasks)
+		if len(tasks) != want {
+			t.Errorf("GET /tasks%s: expected %d tasks, got %d", query, want, len(tasks))
+		}
+	}
+
+	for _, query := range []string{"?q=tag:(", "?status=nope", "?sort=name", "?parent=x"} {
+		if resp := do(t, "GET", server.URL+"/tasks"+query, "", nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET /tasks%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{"GET", "/tasks/42", "", http.StatusNotFound},
+		{"GET", "/tasks/abc", "", http.StatusNotFound},
+		{"GET", "/nope", "", http.StatusNotFound},
+		{"PUT", "/tasks", "", http.StatusMethodNotAllowed},
+		{"POST", "/tasks", `{"name": ""}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"name": "x", "unknown": 1}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"name": "x", "parent": 7}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"name": "x", "priority": "P9"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		resp := do(t, c.method, server.URL+c.path, c.body, nil)
+		if resp.StatusCode != c.want {
+			t.Errorf("%s %s: expected %d, got %d", c.method, c.path, c.want, resp.StatusCode)
+		}
+		var body errorResponse
+		decode(t, resp, &body)
+		if body.Error == "" {
+			t.Errorf("%s %s: expected an error message", c.method, c.path)
+		}
+	}
+}
+
+func TestServer_RequiresJSONBody(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	// A text/plain POST is what a cross-site form or fetch can send without a preflight
+	for _, contentType := range []string{"text/plain", "application/x-www-form-urlencoded", ""} {
+		resp := do(t, "POST", server.URL+"/tasks", `{"name": "x"}`, map[string]string{"Content-Type": contentType})
+		if resp.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("Content-Type %q: expected 415, got %d", contentType, resp.StatusCode)
+		}
+	}
+	if resp := do(t, "POST", server.URL+"/tasks", `{"name": "x"}`, map[string]string{"Content-Type": "application/json; charset=utf-8"}); resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected a JSON body with a charset to be accepted, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_PatchWithIfMatch(t *testing.T) {
+	server, client := newTestServer(t)
+	created, _ := client.Create(context.Background(), task.TaskSpec{Name: "Old", Priority: task.PriorityP2})
+	etag := ETag(created)
+
+	resp := do(t, "PATCH", server.URL+"/tasks/1", `{"name": "New", "priority": null, "due_at": "+3d"}`, map[string]string{"If-Match": etag})
+	var patched task.Task
+	decode(t, resp, &patched)
+	if resp.StatusCode != http.StatusOK || patched.Name != "New" || patched.Priority != task.PriorityNone || patched.DueAt == nil {
+		t.Fatalf("Expected patch to apply, got %d %+v", resp.StatusCode, patched)
+	}
+	if resp.Header.Get("ETag") == etag {
+		t.Errorf("Expected the ETag to change")
+	}
+
+	resp = do(t, "PATCH", server.URL+"/tasks/1", `{"name": "Stale"}`, map[string]string{"If-Match": etag})
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale ETag, got %d", resp.StatusCode)
+	}
+	if got, _ := client.Get(context.Background(), 1); got.Name != "New" {
+		t.Errorf("Expected the stale patch not to be applied, got %q", got.Name)
+	}
+
+	if resp := do(t, "PATCH", server.URL+"/tasks/1", `{"status": "done"}`, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 when patching the status, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_StatusAndDelete(t *testing.T) {
+	server, client := newTestServer(t)
+	parent, _ := client.Create(context.Background(), task.TaskSpec{Name: "Parent"})
+	client.Create(context.Background(), task.TaskSpec{Name: "Child", Parent: parent.ID})
+
+	resp := do(t, "POST", server.URL+"/tasks/1/status", `{"status": "done", "reason": "shipped", "cascade": true}`, map[string]string{"If-Match": ETag(parent)})
+	var changed task.Task
+	decode(t, resp, &changed)
+	if resp.StatusCode != http.StatusOK || changed.Status != task.StatusDone || changed.History[0].Reason != "shipped" {
+		t.Fatalf("Expected status change, got %d %+v", resp.StatusCode, changed)
+	}
+	if child, _ := client.Get(context.Background(), 2); child.Status != task.StatusDone {
+		t.Errorf("Expected cascade to close the subtask, got %s", child.Status)
+	}
+
+	if resp := do(t, "POST", server.URL+"/tasks/1/status", `{"status": "open"}`, map[string]string{"If-Match": ETag(parent)}); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale ETag, got %d", resp.StatusCode)
+	}
+	if resp := do(t, "POST", server.URL+"/tasks/1/status", `{"status": "nope"}`, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown status, got %d", resp.StatusCode)
+	}
+
+	if resp := do(t, "DELETE", server.URL+"/tasks/1", "", nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 when deleting a parent, got %d", resp.StatusCode)
+	}
+	if resp := do(t, "DELETE", server.URL+"/tasks/2", "", map[string]string{"If-Match": `"2-1"`}); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale ETag, got %d", resp.StatusCode)
+	}
+	if resp := do(t, "DELETE", server.URL+"/tasks/2", "", map[string]string{"If-Match": "*"}); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", resp.StatusCode)
+	}
+	if resp := do(t, "GET", server.URL+"/tasks/2", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected deleted task to be gone, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp := do(t, "GET", server.URL+"/openapi.json", "", nil)
+	var doc struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	decode(t, resp, &doc)
+
+	want := map[string][]string{
+		"/tasks":             {"get", "post"},
+		"/tasks/{id}":        {"get", "patch", "delete"},
+		"/tasks/{id}/status": {"post"},
+	}
+	for path, methods := range want {
+		for _, method := range methods {
+			if _, ok := doc.Paths[path][method]; !ok {
+				t.Errorf("Expected OpenAPI document to describe %s %s", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mad01/uni/internal/task"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file inside a data directory
+const FileName = "config"
+
+// Config holds the settings read from the config file in the data directory
+type Config struct {
+	// Store selects the storage backend (json, memory, ...)
+	Store string `yaml:"store,omitempty"`
+	// Project is the project used when none is selected, see uni project
+	Project string `yaml:"project,omitempty"`
+	// Workflow replaces the built-in statuses and transitions when set
+	Workflow *task.Workflow `yaml:"workflow,omitempty"`
+	// Views are saved list invocations, run with uni view <name> or uni list @<name>
+	Views map[string]View `yaml:"views,omitempty"`
+}
+
+// View is a named query together with how to show its result
+type View struct {
+	Description string   `yaml:"description,omitempty"`
+	Query       string   `yaml:"query,omitempty"`
+	Sort        string   `yaml:"sort,omitempty"`
+	Output      string   `yaml:"output,omitempty"`
+	Columns     []string `yaml:"columns,omitempty"`
+	Tree        bool     `yaml:"tree,omitempty"`
+}
+
+// GlobalDir returns the directory of the global config, ~/.uni
+func GlobalDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".uni"), nil
+}
+
+// Load reads dataDir/config and adds the views from the global config in
+// ~/.uni/config that dataDir/config doesn't define itself. All other settings
+// come from dataDir/config only. A missing file yields an empty config.
+func Load(dataDir string) (*Config, error) {
+	cfg, err := loadFile(filepath.Join(dataDir, FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	globalDir, err := GlobalDir()
+	if err != nil || sameDir(dataDir, globalDir) {
+		return cfg, nil
+	}
+
+	global, err := loadFile(filepath.Join(globalDir, FileName))
+	if err != nil {
+		return nil, err
+	}
+	for name, view := range global.Views {
+		if _, ok := cfg.Views[name]; !ok {
+			if cfg.Views == nil {
+				cfg.Views = map[string]View{}
+			}
+			cfg.Views[name] = view
+		}
+	}
+	return cfg, nil
+}
+
+// loadFile parses a single config file; a missing file yields an empty config
+func loadFile(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// sameDir reports whether two paths refer to the same directory
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
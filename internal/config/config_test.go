@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func TestLoad_MergesGlobalViews(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeConfig(t, filepath.Join(home, ".uni"), `
+store: sqlite
+views:
+  standup:
+    query: is:left
+  mine:
+    query: tag:me
+`)
+	repo := filepath.Join(t.TempDir(), ".uni")
+	writeConfig(t, repo, `
+views:
+  standup:
+    query: status:working
+    sort: priority
+    columns: [id, name]
+`)
+
+	cfg, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Store != "" {
+		t.Errorf("Expected only views to come from the global config, got store %q", cfg.Store)
+	}
+	if len(cfg.Views) != 2 {
+		t.Fatalf("Expected 2 views, got %v", cfg.Views)
+	}
+	if standup := cfg.Views["standup"]; standup.Query != "status:working" || standup.Sort != "priority" || len(standup.Columns) != 2 {
+		t.Errorf("Expected the repo standup view to win, got %+v", standup)
+	}
+	if mine := cfg.Views["mine"]; mine.Query != "tag:me" {
+		t.Errorf("Expected the global mine view, got %+v", mine)
+	}
+
+	// Loading the global directory itself reads it once
+	cfg, err = Load(filepath.Join(home, ".uni"))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Store != "sqlite" || len(cfg.Views) != 2 {
+		t.Errorf("Expected the global config, got %+v", cfg)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Store != "" || cfg.Workflow != nil || len(cfg.Views) != 0 {
+		t.Errorf("Expected empty config, got %+v", cfg)
+	}
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
+)
+
+// openClient opens an empty in-memory store
+func openClient(t *testing.T) *uni.Client {
+	t.Helper()
+	client, err := uni.Open(context.Background(), uni.WithDataDir(t.TempDir()), uni.WithBackend("memory"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// exchange sends the messages to a server and returns its responses by ID
+func exchange(t *testing.T, client *uni.Client, messages ...string) map[string]response {
+	t.Helper()
+	var out bytes.Buffer
+	if err := NewServer(client, "test").Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")), &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	responses := map[string]response{}
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp response
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		responses[string(resp.ID)] = resp
+	}
+	return responses
+}
+
+// toolText returns the text of a tools/call result and whether it is an error
+func toolText(t *testing.T, resp response) (string, bool) {
+	t.Helper()
+	data, _ := json.Marshal(resp.Result)
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("Unexpected tool result: %s (%v)", data, resp.Error)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func TestServer_Handshake(t *testing.T) {
+	responses := exchange(t, openClient(t),
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05", "capabilities": {}, "clientInfo": {"name": "test", "version": "1"}}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`,
+		`{"jsonrpc": "2.0", "id": "p", "method": "ping"}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "resources/list"}`,
+		`not json`,
+	)
+
+	if len(responses) != 5 {
+		t.Fatalf("Expected 5 responses (none for the notification), got %d", len(responses))
+	}
+
+	initialized, _ := json.Marshal(responses["1"].Result)
+	if !strings.Contains(string(initialized), `"protocolVersion":"2024-11-05"`) || !strings.Contains(string(initialized), `"tools":{}`) {
+		t.Errorf("Unexpected initialize result: %s", initialized)
+	}
+
+	list, _ := json.Marshal(responses["2"].Result)
+	for _, name := range []string{"add_task", "list_tasks", "get_task", "set_task_status", "edit_task"} {
+		if !strings.Contains(string(list), `"name":"`+name+`"`) {
+			t.Errorf("Expected tool %s to be listed", name)
+		}
+	}
+
+	if responses[`"p"`].Error != nil {
+		t.Errorf("Expected ping to succeed, got %v", responses[`"p"`].Error)
+	}
+	if e := responses["3"].Error; e == nil || e.Code != codeMethodNotFound {
+		t.Errorf("Expected method not found, got %v", e)
+	}
+	if e := responses["null"].Error; e == nil || e.Code != codeParseError {
+		t.Errorf("Expected parse error, got %v", e)
+	}
+}
+
+func TestServer_Tools(t *testing.T) {
+	client := openClient(t)
+	client.Create(context.Background(), task.TaskSpec{Name: "Existing"})
+
+	responses := exchange(t, client,
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "add_task", "arguments": {"name": "Fix login", "tags": ["auth"], "priority": "P1", "due": "2024-05-31"}}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "set_task_status", "arguments": {"id": 2, "status": "working", "reason": "on it"}}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "edit_task", "arguments": {"id": 2, "description": "Session expires", "priority": "none"}}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "list_tasks", "arguments": {"query": "is:left tag:auth"}}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "tools/call", "params": {"name": "get_task", "arguments": {"id": 42}}}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "tools/call", "params": {"name": "list_tasks", "arguments": {"query": "tag:("}}}`,
+		`{"jsonrpc": "2.0", "id": 7, "method": "tools/call", "params": {"name": "delete_everything", "arguments": {}}}`,
+		`{"jsonrpc": "2.0", "id": 8, "method": "tools/call", "params": {"name": "add_task", "arguments": {"name": "x", "colour": "red"}}}`,
+	)
+
+	if _, isError := toolText(t, responses["1"]); isError {
+		t.Fatalf("Expected add_task to succeed")
+	}
+	got, _ := client.Get(context.Background(), 2)
+	if got.Name != "Fix login" || got.Status != task.StatusWorking || got.History[0].Reason != "on it" {
+		t.Errorf("Expected task to be created and moved to working, got %+v", got)
+	}
+	if got.Description != "Session expires" || got.Priority != task.PriorityNone || got.DueAt == nil {
+		t.Errorf("Expected edit_task to change only the given fields, got %+v", got)
+	}
+
+	text, _ := toolText(t, responses["4"])
+	var listed []task.Task
+	if err := json.Unmarshal([]byte(text), &listed); err != nil || len(listed) != 1 || listed[0].ID != 2 {
+		t.Errorf("Expected list_tasks to return task 2, got %s", text)
+	}
+
+	for _, id := range []string{"5", "6", "8"} {
+		if text, isError := toolText(t, responses[id]); !isError || text == "" {
+			t.Errorf("Expected call %s to report a tool error, got %q", id, text)
+		}
+	}
+	if e := responses["7"].Error; e == nil || e.Code != codeInvalidParams {
+		t.Errorf("Expected unknown tool to be a protocol error, got %v", e)
+	}
+}
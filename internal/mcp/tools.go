@@ -0,0 +1,256 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
+)
+
+// tool is an operation offered to MCP clients
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	run         func(ctx context.Context, client *uni.Client, args json.RawMessage) (interface{}, error)
+}
+
+// tools are announced in tools/list in this order
+var tools = []tool{
+	{
+		Name:        "add_task",
+		Description: "Create a task. Returns the created task.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "description": "Short title of the task"},
+				"description": {"type": "string"},
+				"parent": {"type": "integer", "description": "ID of the task this one is a subtask of"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"priority": {"type": "string", "enum": ["P0", "P1", "P2", "P3"], "description": "P0 is the most urgent"},
+				"due": {"type": "string", "description": "YYYY-MM-DD, today, tomorrow, a weekday, +3d or +2w"}
+			},
+			"required": ["name"]
+		}`),
+		run: addTask,
+	},
+	{
+		Name:        "list_tasks",
+		Description: "List tasks sorted by ID (or by sort). query uses the uni list query language: field:value terms such as status:working, is:left, is:closed, is:overdue, tag:backend, priority<=P1, due<7d, parent:3, and bare words matched against name and description, combined with and, or, not/- and parentheses.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"query": {"type": "string", "description": "e.g. 'is:left tag:backend -priority:P3'"},
+				"left": {"type": "boolean", "description": "Only tasks in an active status"},
+				"closed": {"type": "boolean", "description": "Only tasks in a closed status"},
+				"tags": {"type": "array", "items": {"type": "string"}, "description": "Only tasks carrying all of these tags"},
+				"sort": {"type": "string", "enum": ["id", "priority", "due"]}
+			}
+		}`),
+		run: listTasks,
+	},
+	{
+		Name:        "get_task",
+		Description: "Get a task with its status history.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer"}
+			},
+			"required": ["id"]
+		}`),
+		run: getTask,
+	},
+	{
+		Name:        "set_task_status",
+		Description: "Move a task to another status of the workflow, by default open, working, blocked, done or cancel. Returns the updated task.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer"},
+				"status": {"type": "string"},
+				"reason": {"type": "string", "description": "Why the status changed; kept in the history"},
+				"cascade": {"type": "boolean", "description": "When closing, close all open subtasks as well"}
+			},
+			"required": ["id", "status"]
+		}`),
+		run: setTaskStatus,
+	},
+	{
+		Name:        "edit_task",
+		Description: "Change the name, description, tags, priority or due date of a task. Only the given fields change; priority and due accept \"none\" to clear them. Returns the updated task.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer"},
+				"name": {"type": "string"},
+				"description": {"type": "string"},
+				"tags": {"type": "array", "items": {"type": "string"}, "description": "Replaces all tags"},
+				"priority": {"type": "string", "description": "P0-P3 or none"},
+				"due": {"type": "string", "description": "YYYY-MM-DD, today, tomorrow, a weekday, +3d, +2w or none"}
+			},
+			"required": ["id"]
+		}`),
+		run: editTask,
+	},
+}
+
+func findTool(name string) (tool, bool) {
+	for _, t := range tools {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return tool{}, false
+}
+
+// decodeArgs unmarshals tool arguments, rejecting unknown fields
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(strings.NewReader(string(args)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+func addTask(ctx context.Context, client *uni.Client, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Parent      int      `json:"parent"`
+		Tags        []string `json:"tags"`
+		Priority    string   `json:"priority"`
+		Due         string   `json:"due"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Name) == "" {
+		return nil, fmt.Errorf("task name cannot be empty")
+	}
+
+	due, err := parseDue(args.Due)
+	if err != nil {
+		return nil, err
+	}
+	return client.Create(ctx, task.TaskSpec{
+		Name:        strings.TrimSpace(args.Name),
+		Description: args.Description,
+		Parent:      args.Parent,
+		Tags:        args.Tags,
+		Priority:    task.Priority(args.Priority),
+		DueAt:       due,
+	})
+}
+
+func listTasks(ctx context.Context, client *uni.Client, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Query  string   `json:"query"`
+		Left   bool     `json:"left"`
+		Closed bool     `json:"closed"`
+		Tags   []string `json:"tags"`
+		Sort   string   `json:"sort"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	return client.List(ctx, uni.ListOptions{
+		Query:  args.Query,
+		Left:   args.Left,
+		Closed: args.Closed,
+		Tags:   args.Tags,
+		Sort:   args.Sort,
+	})
+}
+
+func getTask(ctx context.Context, client *uni.Client, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID int `json:"id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	return client.Get(ctx, args.ID)
+}
+
+func setTaskStatus(ctx context.Context, client *uni.Client, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID      int    `json:"id"`
+		Status  string `json:"status"`
+		Reason  string `json:"reason"`
+		Cascade bool   `json:"cascade"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	return client.SetStatus(ctx, args.ID, task.StatusChange{
+		Status:  task.TaskStatus(strings.ToLower(strings.TrimSpace(args.Status))),
+		Reason:  args.Reason,
+		Cascade: args.Cascade,
+	})
+}
+
+func editTask(ctx context.Context, client *uni.Client, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID          int       `json:"id"`
+		Name        *string   `json:"name"`
+		Description *string   `json:"description"`
+		Tags        *[]string `json:"tags"`
+		Priority    *string   `json:"priority"`
+		Due         *string   `json:"due"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	t, err := client.Get(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if args.Name != nil {
+		name := strings.TrimSpace(*args.Name)
+		if name == "" {
+			return nil, fmt.Errorf("task name cannot be empty")
+		}
+		t.Name = name
+	}
+	if args.Description != nil {
+		t.Description = *args.Description
+	}
+	if args.Tags != nil {
+		t.Tags = *args.Tags
+	}
+	if args.Priority != nil {
+		t.Priority = task.Priority(*args.Priority)
+	}
+	if args.Due != nil {
+		if t.DueAt, err = parseDue(*args.Due); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := client.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseDue accepts the due dates of uni add --due; empty or "none" means no due date
+func parseDue(input string) (*time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" || strings.EqualFold(input, "none") {
+		return nil, nil
+	}
+	due, err := task.ParseDue(input, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
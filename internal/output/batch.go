@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// WriteStatusResults writes the per-task outcome of a batch status change to w.
+// json and yaml get one entry per ID, text a table; other formats list the
+// tasks that were changed.
+func WriteStatusResults(w io.Writer, results []task.StatusResult, format string, opts Options) error {
+	switch format {
+	case "json":
+		return formatJSON(w, results)
+	case "yaml":
+		return formatYAML(w, results)
+	case "text":
+		return formatStatusResultsText(w, results)
+	default:
+		changed := []task.Task{}
+		for _, r := range results {
+			if r.OK {
+				changed = append(changed, *r.Task)
+			}
+		}
+		return WriteTasks(w, changed, format, opts)
+	}
+}
+
+func formatStatusResultsText(w io.Writer, results []task.StatusResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tRESULT\tSTATUS\tNAME\tERROR")
+	for _, r := range results {
+		if !r.OK {
+			fmt.Fprintf(tw, "%d\tFAILED\t\t\t%s\n", r.ID, textCell(r.Error))
+			continue
+		}
+		fmt.Fprintf(tw, "%d\tOK\t%s\t%s\t\n", r.ID, strings.ToUpper(string(r.Task.Status)), textCell(r.Task.Name))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// Column is a task field that can be shown in tabular output
+type Column struct {
+	// Name is how the column is selected, e.g. with --columns
+	Name   string
+	Header string
+	// Value renders the field for machine-readable formats (csv, tsv)
+	Value func(t task.Task) string
+	// Display, if set, renders the field for people (text, markdown) instead of Value
+	Display func(t task.Task) string
+}
+
+// display renders the column's value for people
+func (c Column) display(t task.Task) string {
+	if c.Display != nil {
+		return c.Display(t)
+	}
+	return c.Value(t)
+}
+
+// columns lists every selectable column in their default order
+var columns = []Column{
+	{Name: "id", Header: "ID", Value: func(t task.Task) string { return strconv.Itoa(t.ID) }},
+	{
+		Name: "status", Header: "STATUS",
+		Value:   func(t task.Task) string { return string(t.Status) },
+		Display: func(t task.Task) string { return strings.ToUpper(string(t.Status)) },
+	},
+	{Name: "priority", Header: "PRIORITY", Value: func(t task.Task) string { return string(t.Priority) }},
+	{Name: "due", Header: "DUE", Value: dueText},
+	{Name: "name", Header: "NAME", Value: func(t task.Task) string { return t.Name }},
+	{Name: "tags", Header: "TAGS", Value: func(t task.Task) string { return strings.Join(t.Tags, ",") }},
+	{Name: "description", Header: "DESCRIPTION", Value: func(t task.Task) string { return t.Description }},
+	{Name: "parent", Header: "PARENT", Value: func(t task.Task) string { return optionalID(t.Parent) }},
+	{Name: "blocked_by", Header: "BLOCKED BY", Value: func(t task.Task) string { return joinIDs(t.BlockedBy) }},
+	{Name: "project", Header: "PROJECT", Value: projectText},
+	{Name: "store", Header: "STORE", Value: func(t task.Task) string { return t.Store }},
+	{
+		Name: "created", Header: "CREATED",
+		Value:   func(t task.Task) string { return t.CreatedAt.Format(time.RFC3339) },
+		Display: func(t task.Task) string { return t.CreatedAt.Local().Format(time.DateTime) },
+	},
+	{
+		Name: "updated", Header: "UPDATED",
+		Value:   func(t task.Task) string { return t.UpdatedAt.Format(time.RFC3339) },
+		Display: func(t task.Task) string { return t.UpdatedAt.Local().Format(time.DateTime) },
+	},
+}
+
+// columnAliases lets columns also be selected by their JSON field names
+var columnAliases = map[string]string{
+	"due_at":     "due",
+	"created_at": "created",
+	"updated_at": "updated",
+}
+
+// DefaultColumns are shown when no columns are selected
+var DefaultColumns = []string{"id", "status", "priority", "due", "name", "tags", "description"}
+
+// ColumnNames returns the names of all selectable columns
+func ColumnNames() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ParseColumns looks up columns by name; no names selects DefaultColumns
+func ParseColumns(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+
+	selected := make([]Column, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := columnAliases[key]; ok {
+			key = alias
+		}
+
+		found := false
+		for _, c := range columns {
+			if c.Name == key {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q. Valid columns: %v", name, ColumnNames())
+		}
+	}
+	return selected, nil
+}
+
+// Options tweak how a list of tasks is rendered
+type Options struct {
+	// Columns selects the columns of the tabular formats; empty means DefaultColumns
+	Columns []string
+	// Workflow colors the statuses and decides which tasks are closed or
+	// overdue; nil means task.DefaultWorkflow
+	Workflow *task.Workflow
+	// StoreWorkflows holds the workflows of other stores by name; a task read
+	// from one of them, see Task.Store, is rendered with its store's workflow
+	StoreWorkflows map[string]*task.Workflow
+}
+
+var defaultWorkflow = task.DefaultWorkflow()
+
+// workflow returns the workflow t is rendered with
+func (o Options) workflow(t task.Task) *task.Workflow {
+	if w, ok := o.StoreWorkflows[t.Store]; ok && t.Store != "" {
+		return w
+	}
+	if o.Workflow == nil {
+		return defaultWorkflow
+	}
+	return o.Workflow
+}
+
+// projectText names the project of t, which is empty for the default project
+func projectText(t task.Task) string {
+	if t.Project == "" {
+		return task.DefaultProject
+	}
+	return t.Project
+}
+
+func optionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
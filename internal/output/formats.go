@@ -0,0 +1,108 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// Format renders tasks in one output format
+type Format interface {
+	// Tasks writes a list of tasks to w
+	Tasks(w io.Writer, tasks []task.Task, opts Options) error
+	// Task writes a single task in detail to w
+	Task(w io.Writer, t *task.Task, opts Options) error
+}
+
+// FormatFactory creates a format from the argument given after the name,
+// e.g. the template in -o template='{{.ID}}'
+type FormatFactory func(arg string) (Format, error)
+
+var formats = map[string]FormatFactory{
+	"normal": builtin("normal", funcFormat{
+		tasks: formatTasksNormal,
+		task:  formatTaskNormal,
+	}),
+	"text": builtin("text", funcFormat{
+		tasks: func(w io.Writer, tasks []task.Task, opts Options) error {
+			cols, err := ParseColumns(opts.Columns)
+			if err != nil {
+				return err
+			}
+			return formatTasksText(w, tasks, cols)
+		},
+		task: formatTaskText,
+	}),
+	"json": builtin("json", funcFormat{
+		tasks: func(w io.Writer, tasks []task.Task, _ Options) error { return formatJSON(w, tasks) },
+		task:  func(w io.Writer, t *task.Task, _ Options) error { return formatJSON(w, []*task.Task{t}) },
+	}),
+	"yaml": builtin("yaml", funcFormat{
+		tasks: func(w io.Writer, tasks []task.Task, _ Options) error { return formatYAML(w, tasks) },
+		task:  func(w io.Writer, t *task.Task, _ Options) error { return formatYAML(w, []*task.Task{t}) },
+	}),
+	"csv":           builtin("csv", tableFormat(writeCSV)),
+	"tsv":           builtin("tsv", tableFormat(writeTSV)),
+	"markdown":      builtin("markdown", tableFormat(writeMarkdown)),
+	"ics":           builtin("ics", icsFormat),
+	"template":      newTemplateFormat,
+	"template-file": newTemplateFileFormat,
+}
+
+// RegisterFormat makes an output format available under name
+func RegisterFormat(name string, factory FormatFactory) {
+	formats[name] = factory
+}
+
+// FormatNames returns the names of all registered output formats
+func FormatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseFormat looks up the format named by spec, which is either a name or name=argument
+func ParseFormat(spec string) (Format, error) {
+	name, arg, _ := strings.Cut(spec, "=")
+	factory, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid output format: %s. Valid formats: %v", name, FormatNames())
+	}
+	return factory(arg)
+}
+
+// ValidateFormat returns an error unless spec names a usable output format
+func ValidateFormat(spec string) error {
+	_, err := ParseFormat(spec)
+	return err
+}
+
+// funcFormat adapts a pair of functions to Format
+type funcFormat struct {
+	tasks func(w io.Writer, tasks []task.Task, opts Options) error
+	task  func(w io.Writer, t *task.Task, opts Options) error
+}
+
+func (f funcFormat) Tasks(w io.Writer, tasks []task.Task, opts Options) error {
+	return f.tasks(w, tasks, opts)
+}
+
+func (f funcFormat) Task(w io.Writer, t *task.Task, opts Options) error {
+	return f.task(w, t, opts)
+}
+
+// builtin wraps a format that takes no argument
+func builtin(name string, f Format) FormatFactory {
+	return func(arg string) (Format, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("output format %s does not take an argument", name)
+		}
+		return f, nil
+	}
+}
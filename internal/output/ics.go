@@ -0,0 +1,144 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// icsTimeLayout is the UTC date-time form of RFC 5545
+const icsTimeLayout = "20060102T150405Z"
+
+// icsPriorities maps task priorities onto the 1 (highest) to 9 (lowest) scale of RFC 5545
+var icsPriorities = map[task.Priority]int{
+	task.PriorityP0: 1,
+	task.PriorityP1: 3,
+	task.PriorityP2: 5,
+	task.PriorityP3: 7,
+}
+
+// icsFormat writes tasks as VTODO entries of an iCalendar file
+var icsFormat = funcFormat{
+	tasks: writeICS,
+	task:  func(w io.Writer, t *task.Task, opts Options) error { return writeICS(w, []task.Task{*t}, opts) },
+}
+
+// writeICS writes an RFC 5545 calendar with one VTODO per task
+func writeICS(w io.Writer, tasks []task.Task, opts Options) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICSLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//uni//uni task export//EN")
+	line("CALSCALE", "GREGORIAN")
+	for _, t := range tasks {
+		line("BEGIN", "VTODO")
+		line("UID", icsUID(t.ID, t.CreatedAt))
+		line("DTSTAMP", t.UpdatedAt.UTC().Format(icsTimeLayout))
+		line("CREATED", t.CreatedAt.UTC().Format(icsTimeLayout))
+		line("LAST-MODIFIED", t.UpdatedAt.UTC().Format(icsTimeLayout))
+		line("SUMMARY", icsEscape(t.Name))
+		if t.Description != "" {
+			line("DESCRIPTION", icsEscape(t.Description))
+		}
+
+		status := icsStatus(opts.workflow(t), t.Status)
+		line("STATUS", status)
+		if status == "COMPLETED" {
+			line("COMPLETED", closedAt(t).UTC().Format(icsTimeLayout))
+		}
+		if p, ok := icsPriorities[t.Priority]; ok {
+			line("PRIORITY", fmt.Sprint(p))
+		}
+		if t.DueAt != nil {
+			writeICSLine(bw, "DUE;VALUE=DATE:"+t.DueAt.Format("20060102"))
+		}
+		if len(t.Tags) > 0 {
+			tags := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				tags[i] = icsEscape(tag)
+			}
+			line("CATEGORIES", strings.Join(tags, ","))
+		}
+		if t.Parent != 0 {
+			if parent := findTask(tasks, t.Parent); parent != nil {
+				line("RELATED-TO", icsUID(parent.ID, parent.CreatedAt))
+			}
+		}
+		line("END", "VTODO")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// icsUID returns a UID that stays the same across exports of the same task
+func icsUID(id int, createdAt time.Time) string {
+	return fmt.Sprintf("task-%d-%d@uni", id, createdAt.Unix())
+}
+
+// icsStatus maps a status of workflow onto the VTODO statuses
+func icsStatus(workflow *task.Workflow, status task.TaskStatus) string {
+	switch status {
+	case task.StatusWorking:
+		return "IN-PROCESS"
+	case task.StatusCancel:
+		return "CANCELLED"
+	case task.StatusDone:
+		return "COMPLETED"
+	}
+	if workflow.IsClosed(status) {
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// closedAt returns when the task last moved to its current status
+func closedAt(t task.Task) time.Time {
+	for i := len(t.History) - 1; i >= 0; i-- {
+		if t.History[i].To == t.Status {
+			return t.History[i].At
+		}
+	}
+	return t.UpdatedAt
+}
+
+func findTask(tasks []task.Task, id int) *task.Task {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// icsEscaper escapes TEXT values as required by RFC 5545 section 3.3.11
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// writeICSLine writes a content line, folding it after 75 octets without
+// splitting a UTF-8 character, and ends it with CRLF
+func writeICSLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = 74
+	}
+	w.WriteString(line + "\r\n")
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+func TestWriteICS(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	closed := created.Add(2 * time.Hour)
+	due := time.Date(2024, 5, 31, 0, 0, 0, 0, time.Local)
+	tasks := []task.Task{
+		{ID: 1, Name: "Ship, then; rest", Description: "a\\b\nc", Status: task.StatusWorking, Priority: task.PriorityP0, DueAt: &due, Tags: []string{"x", "y"}, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, Name: "Done", Status: task.StatusDone, Parent: 1, CreatedAt: created, UpdatedAt: closed,
+			History: []task.Transition{{From: task.StatusOpen, To: task.StatusDone, At: closed}}},
+		{ID: 3, Name: "Dropped", Status: task.StatusCancel, Priority: task.PriorityP3, CreatedAt: created, UpdatedAt: created},
+		{ID: 4, Name: "Waiting", Status: task.StatusBlocked, CreatedAt: created, UpdatedAt: created},
+	}
+
+	var buf bytes.Buffer
+	if err := writeICS(&buf, tasks, Options{}); err != nil {
+		t.Fatalf("Failed to write ics: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:task-1-1714554000@uni\r\n",
+		`SUMMARY:Ship\, then\; rest` + "\r\n",
+		`DESCRIPTION:a\\b\nc` + "\r\n",
+		"STATUS:IN-PROCESS\r\nPRIORITY:1\r\nDUE;VALUE=DATE:20240531\r\nCATEGORIES:x,y\r\n",
+		"STATUS:COMPLETED\r\nCOMPLETED:20240501T110000Z\r\n",
+		"RELATED-TO:task-1-1714554000@uni\r\n",
+		"STATUS:CANCELLED\r\nPRIORITY:7\r\n",
+		"STATUS:NEEDS-ACTION\r\n",
+		"END:VTODO\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected ics output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VTODO"); n != 4 {
+		t.Errorf("Expected 4 VTODO entries, got %d", n)
+	}
+}
+
+func TestWriteICSLineFolding(t *testing.T) {
+	var buf bytes.Buffer
+	long := strings.Repeat("ü", 60)
+	if err := writeICS(&buf, []task.Task{{ID: 1, Name: long}}, Options{}); err != nil {
+		t.Fatalf("Failed to write ics: %v", err)
+	}
+
+	var summary string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary = line
+		} else if summary != "" && strings.HasPrefix(line, " ") {
+			summary += line[1:]
+		} else if summary != "" {
+			break
+		}
+	}
+	if summary != "SUMMARY:"+long {
+		t.Errorf("Expected folded summary to unfold to the name, got %q", summary)
+	}
+}
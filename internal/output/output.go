@@ -3,130 +3,319 @@ package output
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"hash/fnv"
+	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/mad01/uni/internal/task"
 	"gopkg.in/yaml.v3"
 )
 
-// FormatTasks formats tasks according to the specified output format
-func FormatTasks(tasks []task.Task, format string) error {
-	switch format {
-	case "json":
-		return formatJSON(tasks)
-	case "yaml":
-		return formatYAML(tasks)
-	case "text":
-		return formatText(tasks)
-	case "normal":
-		return formatNormal(tasks)
-	default:
-		return fmt.Errorf("unsupported output format: %s", format)
+// WriteTasks writes tasks to w in the specified output format
+func WriteTasks(w io.Writer, tasks []task.Task, format string, opts Options) error {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	return f.Tasks(w, tasks, opts)
+}
+
+// WriteTask writes a single task to w in the specified output format
+func WriteTask(w io.Writer, t *task.Task, format string, opts Options) error {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return err
 	}
+	return f.Task(w, t, opts)
 }
 
-// FormatTask formats a single task according to the specified output format
-func FormatTask(t *task.Task, format string) error {
+// HistoryEntry is a status transition together with the time spent in the previous status
+type HistoryEntry struct {
+	From     task.TaskStatus `json:"from"`
+	To       task.TaskStatus `json:"to"`
+	At       time.Time       `json:"at"`
+	Reason   string          `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Duration string          `json:"duration"`
+}
+
+// WriteHistory writes the status history of a task to w in the specified output format
+func WriteHistory(w io.Writer, t *task.Task, format string, opts Options) error {
+	entries := historyEntries(t)
 	switch format {
 	case "json":
-		return formatJSON([]*task.Task{t})
+		return formatJSON(w, entries)
 	case "yaml":
-		return formatYAML([]*task.Task{t})
+		return formatYAML(w, entries)
 	case "text":
-		return formatText([]*task.Task{t})
+		return formatHistoryText(w, entries)
 	case "normal":
-		return formatNormal([]*task.Task{t})
+		if len(entries) == 0 {
+			fmt.Fprintf(w, "Task #%d has no status changes.\n", t.ID)
+			return nil
+		}
+		formatHistoryNormal(w, entries, opts.workflow(*t))
+		return nil
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
 }
 
-func formatJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
+// historyEntries pairs each transition with how long the task stayed in the status it left
+func historyEntries(t *task.Task) []HistoryEntry {
+	entries := []HistoryEntry{}
+	since := t.CreatedAt
+	for _, tr := range t.History {
+		entries = append(entries, HistoryEntry{
+			From:     tr.From,
+			To:       tr.To,
+			At:       tr.At,
+			Reason:   tr.Reason,
+			Duration: formatDuration(tr.At.Sub(since)),
+		})
+		since = tr.At
+	}
+	return entries
+}
+
+func formatJSON(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
-func formatYAML(data interface{}) error {
-	encoder := yaml.NewEncoder(os.Stdout)
+func formatYAML(w io.Writer, data interface{}) error {
+	encoder := yaml.NewEncoder(w)
 	defer encoder.Close()
 	return encoder.Encode(data)
 }
 
-func formatText(data interface{}) error {
-	switch v := data.(type) {
-	case []task.Task:
-		return formatTasksText(v)
-	case []*task.Task:
-		tasks := make([]task.Task, len(v))
-		for i, t := range v {
-			tasks[i] = *t
+func formatTasksText(w io.Writer, tasks []task.Task, cols []Column) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, textHeader(cols))
+	for _, t := range tasks {
+		fmt.Fprintln(tw, textRow(t, cols, ""))
+	}
+	return tw.Flush()
+}
+
+// textHeader renders the tab-separated header line for cols
+func textHeader(cols []Column) string {
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.Header
+	}
+	return strings.Join(headers, "\t")
+}
+
+// textRow renders the tab-separated values of t for cols, putting namePrefix before the name
+func textRow(t task.Task, cols []Column, namePrefix string) string {
+	values := make([]string, len(cols))
+	for i, c := range cols {
+		values[i] = textCell(c.display(t))
+		if c.Name == "name" {
+			values[i] = namePrefix + values[i]
 		}
-		return formatTasksText(tasks)
-	default:
-		return fmt.Errorf("unsupported data type for text format")
 	}
+	return strings.Join(values, "\t")
+}
+
+// textCell keeps a value on one tabwriter cell by turning tabs and line breaks into spaces
+func textCell(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(s)
+}
+
+func formatTaskText(w io.Writer, t *task.Task, _ Options) error {
+	cols, _ := ParseColumns(nil)
+	if err := formatTasksText(w, []task.Task{*t}, cols); err != nil {
+		return err
+	}
+
+	if len(t.History) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	return formatHistoryText(w, historyEntries(t))
+}
+
+func formatHistoryText(w io.Writer, entries []HistoryEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FROM\tTO\tAT\tDURATION\tREASON")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			strings.ToUpper(string(e.From)), strings.ToUpper(string(e.To)),
+			e.At.Format(time.RFC3339), e.Duration, e.Reason)
+	}
+	return tw.Flush()
+}
+
+func formatTaskNormal(w io.Writer, t *task.Task, opts Options) error {
+	printTaskNormal(w, *t, opts)
+	if len(t.History) > 0 {
+		fmt.Fprintln(w, "  History:")
+		formatHistoryNormal(w, historyEntries(t), opts.workflow(*t))
+	}
+	fmt.Fprintln(w)
+	return nil
 }
 
-func formatNormal(data interface{}) error {
-	switch v := data.(type) {
-	case []task.Task:
-		return formatTasksNormal(v)
-	case []*task.Task:
-		tasks := make([]task.Task, len(v))
-		for i, t := range v {
-			tasks[i] = *t
+func formatHistoryNormal(w io.Writer, entries []HistoryEntry, workflow *task.Workflow) {
+	for _, e := range entries {
+		fmt.Fprintf(w, "  %s  %s%s%s -> %s%s%s  (after %s)",
+			e.At.Local().Format("2006-01-02 15:04"),
+			getStatusColor(workflow, e.From), strings.ToUpper(string(e.From)), "\033[0m",
+			getStatusColor(workflow, e.To), strings.ToUpper(string(e.To)), "\033[0m",
+			e.Duration)
+		if e.Reason != "" {
+			fmt.Fprintf(w, " - %s", e.Reason)
 		}
-		return formatTasksNormal(tasks)
-	default:
-		return fmt.Errorf("unsupported data type for normal format")
+		fmt.Fprintln(w)
 	}
 }
 
-func formatTasksText(tasks []task.Task) error {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tSTATUS\tNAME\tDESCRIPTION")
-	for _, t := range tasks {
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", t.ID, strings.ToUpper(string(t.Status)), t.Name, t.Description)
+// formatDuration renders a duration rounded to a readable unit, e.g. 3d4h or 12m
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return d.Round(time.Second).String()
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		days := int(d.Hours()) / 24
+		return fmt.Sprintf("%dd%dh", days, int(d.Hours())%24)
 	}
-	return w.Flush()
 }
 
-func formatTasksNormal(tasks []task.Task) error {
+func formatTasksNormal(w io.Writer, tasks []task.Task, opts Options) error {
 	if len(tasks) == 0 {
-		fmt.Println("No tasks found.")
+		fmt.Fprintln(w, "No tasks found.")
 		return nil
 	}
 
 	for _, t := range tasks {
-		statusColor := getStatusColor(t.Status)
-		fmt.Printf("%s#%d%s [%s%s%s] %s\n",
-			"\033[1m", t.ID, "\033[0m",
-			statusColor, strings.ToUpper(string(t.Status)), "\033[0m",
-			t.Name)
-		if t.Description != "" {
-			fmt.Printf("  %s\n", t.Description)
-		}
-		fmt.Println()
+		printTaskNormal(w, t, opts)
+		fmt.Fprintln(w)
 	}
 	return nil
 }
 
-func getStatusColor(status task.TaskStatus) string {
-	switch status {
-	case task.StatusOpen:
-		return "\033[33m" // Yellow
-	case task.StatusWorking:
-		return "\033[34m" // Blue
-	case task.StatusBlocked:
-		return "\033[35m" // Magenta
-	case task.StatusDone:
-		return "\033[32m" // Green
-	case task.StatusCancel:
-		return "\033[31m" // Red
+func printTaskNormal(w io.Writer, t task.Task, opts Options) {
+	printTaskNormalIndented(w, t, "", "  ", opts)
+}
+
+// printTaskNormalIndented prints the task header after prefix and its details after indent
+func printTaskNormalIndented(w io.Writer, t task.Task, prefix, indent string, opts Options) {
+	fmt.Fprintf(w, "%s%s\n", prefix, taskHeaderNormal(t, opts.workflow(t)))
+	if t.Description != "" {
+		fmt.Fprintf(w, "%s%s\n", indent, t.Description)
+	}
+	if t.Parent != 0 && prefix == "" {
+		fmt.Fprintf(w, "%ssubtask of #%d\n", indent, t.Parent)
+	}
+	if len(t.BlockedBy) > 0 {
+		ids := make([]string, len(t.BlockedBy))
+		for i, id := range t.BlockedBy {
+			ids[i] = fmt.Sprintf("#%d", id)
+		}
+		fmt.Fprintf(w, "%sblocked by %s\n", indent, strings.Join(ids, ", "))
+	}
+}
+
+// taskHeaderNormal renders the "#ID [STATUS] P1 name" line of the normal format,
+// with the project in front for tasks of a named project and the ID qualified
+// with the store for tasks read by store name
+func taskHeaderNormal(t task.Task, workflow *task.Workflow) string {
+	statusColor := getStatusColor(workflow, t.Status)
+	header := ""
+	if t.Project != "" {
+		header = fmt.Sprintf("%s%s%s ", "\033[90m", t.Project, "\033[0m")
+	}
+	header += fmt.Sprintf("%s%s#%d%s [%s%s%s] ",
+		"\033[1m", t.Store, t.ID, "\033[0m",
+		statusColor, strings.ToUpper(string(t.Status)), "\033[0m")
+	if t.Priority != task.PriorityNone {
+		header += fmt.Sprintf("%s%s%s ", priorityColors[t.Priority], t.Priority, "\033[0m")
+	}
+	header += t.Name
+	if len(t.Tags) > 0 {
+		header += " " + tagChips(t.Tags)
+	}
+	if t.DueAt != nil {
+		header += " " + dueNormal(t, time.Now(), workflow)
+	}
+	return header
+}
+
+// priorityColors highlights the most urgent priorities
+var priorityColors = map[task.Priority]string{
+	task.PriorityP0: "\033[1;31m", // Bold red
+	task.PriorityP1: "\033[33m",   // Yellow
+	task.PriorityP3: "\033[90m",   // Gray
+}
+
+// dueNormal renders the due date, in red when the task is overdue and yellow when it is due today
+func dueNormal(t task.Task, now time.Time, workflow *task.Workflow) string {
+	switch {
+	case workflow.Overdue(t, now):
+		return fmt.Sprintf("%sdue %s (overdue)%s", "\033[1;31m", t.DueAt.Format(task.DueDateLayout), "\033[0m")
+	case workflow.DueToday(t, now):
+		return fmt.Sprintf("%sdue today%s", "\033[1;33m", "\033[0m")
 	default:
-		return "\033[0m" // Reset
+		return fmt.Sprintf("%sdue %s%s", "\033[90m", t.DueAt.Format(task.DueDateLayout), "\033[0m")
+	}
+}
+
+// dueText renders the due date for the text format
+func dueText(t task.Task) string {
+	if t.DueAt == nil {
+		return ""
+	}
+	return t.DueAt.Format(task.DueDateLayout)
+}
+
+// tagPalette holds the background/foreground pairs tags are rendered in
+var tagPalette = []string{
+	"\033[30;46m", // Black on cyan
+	"\033[30;43m", // Black on yellow
+	"\033[30;42m", // Black on green
+	"\033[37;45m", // White on magenta
+	"\033[37;44m", // White on blue
+	"\033[37;41m", // White on red
+}
+
+// tagChips renders tags as colored chips; a tag always gets the same color
+func tagChips(tags []string) string {
+	chips := make([]string, len(tags))
+	for i, tag := range tags {
+		h := fnv.New32a()
+		h.Write([]byte(tag))
+		chips[i] = fmt.Sprintf("%s %s %s", tagPalette[h.Sum32()%uint32(len(tagPalette))], tag, "\033[0m")
+	}
+	return strings.Join(chips, " ")
+}
+
+// colorCodes maps the workflow color names to ANSI escape codes
+var colorCodes = map[string]string{
+	"black":   "\033[30m",
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+	"gray":    "\033[90m",
+}
+
+// getStatusColor returns the ANSI color workflow assigns to status
+func getStatusColor(workflow *task.Workflow, status task.TaskStatus) string {
+	if def, ok := workflow.Status(status); ok {
+		if code, ok := colorCodes[def.Color]; ok {
+			return code
+		}
 	}
+	return "\033[0m" // Reset
 }
@@ -0,0 +1,106 @@
+package output
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+//go:embed templates/report.html
+var reportHTML string
+
+//go:embed templates/report.css
+var reportCSS string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportHTML))
+
+// cssColors maps the workflow color names to the colors used in the HTML report
+var cssColors = map[string]string{
+	"black":   "#24292f",
+	"red":     "#cf222e",
+	"green":   "#2da44e",
+	"yellow":  "#d4a72c",
+	"blue":    "#0969da",
+	"magenta": "#8250df",
+	"cyan":    "#1b7c83",
+	"white":   "#d0d7de",
+	"gray":    "#6e7781",
+}
+
+// reportData is what the report template renders
+type reportData struct {
+	Title       string
+	CSS         template.CSS
+	GeneratedAt time.Time
+	Total       int
+	Overdue     int
+	Groups      []reportGroup
+}
+
+// reportGroup holds the tasks in one status
+type reportGroup struct {
+	Status task.TaskStatus
+	Color  template.CSS
+	Tasks  []reportTask
+}
+
+// reportTask is a task with its history timeline and due state worked out
+type reportTask struct {
+	task.Task
+	Entries  []HistoryEntry
+	Closed   bool
+	Overdue  bool
+	DueToday bool
+}
+
+// WriteHTMLReport writes a self-contained HTML page with the tasks grouped by
+// status in the order of workflow, summary counts and each task's status history
+func WriteHTMLReport(w io.Writer, title string, tasks []task.Task, now time.Time, workflow *task.Workflow) error {
+	data := reportData{
+		Title:       title,
+		CSS:         template.CSS(reportCSS),
+		GeneratedAt: now,
+		Total:       len(tasks),
+	}
+
+	index := map[task.TaskStatus]int{}
+	addGroup := func(status task.TaskStatus) int {
+		color := cssColors["gray"]
+		if def, ok := workflow.Status(status); ok {
+			if c, ok := cssColors[def.Color]; ok {
+				color = c
+			}
+		}
+		index[status] = len(data.Groups)
+		data.Groups = append(data.Groups, reportGroup{Status: status, Color: template.CSS(color)})
+		return index[status]
+	}
+	for _, status := range workflow.StatusNames() {
+		addGroup(status)
+	}
+
+	for _, t := range tasks {
+		i, ok := index[t.Status]
+		if !ok {
+			// Tasks left in a status the workflow no longer declares
+			i = addGroup(t.Status)
+		}
+
+		rt := reportTask{
+			Task:     t,
+			Entries:  historyEntries(&t),
+			Closed:   workflow.IsClosed(t.Status),
+			Overdue:  workflow.Overdue(t, now),
+			DueToday: workflow.DueToday(t, now),
+		}
+		if rt.Overdue {
+			data.Overdue++
+		}
+		data.Groups[i].Tasks = append(data.Groups[i].Tasks, rt)
+	}
+
+	return reportTemplate.Execute(w, data)
+}
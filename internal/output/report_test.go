@@ -0,0 +1,64 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	past := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []task.Task{
+		{ID: 1, Name: "Ship <b>it</b>", Status: task.StatusDone, CreatedAt: now.Add(-48 * time.Hour), History: []task.Transition{
+			{From: task.StatusOpen, To: task.StatusDone, At: now.Add(-24 * time.Hour), Reason: "released & announced"},
+		}},
+		{ID: 2, Name: "Late one", Description: "line one\nline two", Status: task.StatusOpen, DueAt: &past, Tags: []string{"ops"}},
+		{ID: 3, Name: "Legacy", Status: "triage"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, "Weekly <retro>", tasks, now, task.DefaultWorkflow()); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<title>Weekly &lt;retro&gt;</title>",
+		"Ship &lt;b&gt;it&lt;/b&gt;",
+		"released &amp; announced",
+		"after 1d0h",
+		"1 overdue",
+		`class="due overdue"`,
+		`id="status-triage"`,
+		"<details>",
+		"<style>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected report to contain %q", want)
+		}
+	}
+
+	// Groups follow the workflow order, with unknown statuses last
+	open := strings.Index(html, `id="status-open"`)
+	done := strings.Index(html, `id="status-done"`)
+	triage := strings.Index(html, `id="status-triage"`)
+	if !(open < done && done < triage) {
+		t.Errorf("Expected groups in workflow order, got open=%d done=%d triage=%d", open, done, triage)
+	}
+
+	// Empty statuses are counted but get no section
+	if strings.Contains(html, `id="status-working"`) || !strings.Contains(html, `href="#status-working"`) {
+		t.Error("Expected working to appear in the summary only")
+	}
+
+	// Self-contained: no external stylesheets, scripts or images
+	for _, external := range []string{"<link", "<script", "src=", "http://", "https://"} {
+		if strings.Contains(html, external) {
+			t.Errorf("Expected no external assets, found %q", external)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/mad01/uni/internal/search"
+)
+
+// WriteSearchResults writes ranked search results to w in the specified output format
+func WriteSearchResults(w io.Writer, results []search.Result, format string, opts Options) error {
+	switch format {
+	case "json":
+		return formatJSON(w, results)
+	case "yaml":
+		return formatYAML(w, results)
+	case "text":
+		return formatSearchText(w, results)
+	case "normal":
+		return formatSearchNormal(w, results, opts)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func formatSearchText(w io.Writer, results []search.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCORE\tID\tSTATUS\tNAME\tDESCRIPTION")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%.3f\t%d\t%s\t%s\t%s\n", r.Score, r.Task.ID, strings.ToUpper(string(r.Task.Status)), r.Task.Name, r.Task.Description)
+	}
+	return tw.Flush()
+}
+
+func formatSearchNormal(w io.Writer, results []search.Result, opts Options) error {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No matching tasks found.")
+		return nil
+	}
+
+	for _, r := range results {
+		terms := make(map[string]bool, len(r.Terms))
+		for _, term := range r.Terms {
+			terms[term] = true
+		}
+
+		t := r.Task
+		t.Name = highlight(t.Name, terms)
+		fmt.Fprintf(w, "%s  %s(%.2f)%s\n", taskHeaderNormal(t, opts.workflow(t)), "\033[90m", r.Score, "\033[0m")
+		if t.Description != "" {
+			fmt.Fprintf(w, "  %s\n", highlight(t.Description, terms))
+		}
+		for _, tr := range t.History {
+			if tr.Reason != "" && containsTerm(tr.Reason, terms) {
+				fmt.Fprintf(w, "  %snote%s %s\n", "\033[90m", "\033[0m", highlight(tr.Reason, terms))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// highlight marks the words of text that are in terms, comparing case-insensitively
+func highlight(text string, terms map[string]bool) string {
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := string(runes[i:end])
+		if terms[strings.ToLower(word)] {
+			b.WriteString("\033[1;33m" + word + "\033[0m")
+		} else {
+			b.WriteString(word)
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// containsTerm reports whether any word of text is in terms
+func containsTerm(text string, terms map[string]bool) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) }) {
+		if terms[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordRune matches the word characters of the search tokenizer
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
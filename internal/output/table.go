@@ -0,0 +1,106 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// tableWriter writes tasks as a table with the given columns
+type tableWriter func(w io.Writer, tasks []task.Task, cols []Column) error
+
+// tableFormat adapts a table writer to Format; a single task is a one-row table
+func tableFormat(write tableWriter) Format {
+	return funcFormat{
+		tasks: func(w io.Writer, tasks []task.Task, opts Options) error {
+			cols, err := ParseColumns(opts.Columns)
+			if err != nil {
+				return err
+			}
+			return write(w, tasks, cols)
+		},
+		task: func(w io.Writer, t *task.Task, _ Options) error {
+			cols, _ := ParseColumns(nil)
+			return write(w, []task.Task{*t}, cols)
+		},
+	}
+}
+
+// writeCSV writes RFC 4180 CSV with a header of column names
+func writeCSV(w io.Writer, tasks []task.Task, cols []Column) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+	}
+	cw.Write(header)
+
+	for _, t := range tasks {
+		record := make([]string, len(cols))
+		for i, c := range cols {
+			record[i] = c.Value(t)
+		}
+		cw.Write(record)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// tsvEscaper escapes the characters that would break a tab-separated row
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writeTSV writes tab-separated values with a header of column names.
+// Backslashes, tabs and line breaks in values are escaped as \\, \t, \n and \r.
+func writeTSV(w io.Writer, tasks []task.Task, cols []Column) error {
+	bw := bufio.NewWriter(w)
+
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+	}
+	bw.WriteString(strings.Join(header, "\t") + "\n")
+
+	for _, t := range tasks {
+		values := make([]string, len(cols))
+		for i, c := range cols {
+			values[i] = tsvEscaper.Replace(c.Value(t))
+		}
+		bw.WriteString(strings.Join(values, "\t") + "\n")
+	}
+	return bw.Flush()
+}
+
+// markdownEscaper keeps a value inside its table cell: pipes are escaped and
+// line breaks become <br>
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// writeMarkdown writes a GitHub-flavored Markdown table
+func writeMarkdown(w io.Writer, tasks []task.Task, cols []Column) error {
+	bw := bufio.NewWriter(w)
+
+	header := make([]string, len(cols))
+	rule := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Header
+		rule[i] = "---"
+		if c.Name == "id" {
+			rule[i] = "--:"
+		}
+	}
+	bw.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	bw.WriteString("| " + strings.Join(rule, " | ") + " |\n")
+
+	for _, t := range tasks {
+		values := make([]string, len(cols))
+		for i, c := range cols {
+			values[i] = markdownEscaper.Replace(strings.TrimSpace(c.display(t)))
+		}
+		bw.WriteString("| " + strings.Join(values, " | ") + " |\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,128 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+func tableTasks() []task.Task {
+	return []task.Task{
+		{ID: 1, Name: "Plain", Status: task.StatusOpen},
+		{ID: 2, Name: `pipe | and "quotes"`, Description: "line1\nline2\twith tab, comma \\ slash", Status: task.StatusWorking},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	cols, _ := ParseColumns([]string{"id", "status", "name", "description"})
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, tableTasks(), cols); err != nil {
+		t.Fatalf("Failed to write csv: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read back csv: %v", err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != "id,status,name,description" {
+		t.Fatalf("Unexpected csv records: %q", records)
+	}
+	if records[2][1] != "working" || records[2][2] != `pipe | and "quotes"` || records[2][3] != tableTasks()[1].Description {
+		t.Errorf("Expected values to survive a csv round trip, got %q", records[2])
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	cols, _ := ParseColumns([]string{"id", "description"})
+	var buf bytes.Buffer
+	if err := writeTSV(&buf, tableTasks(), cols); err != nil {
+		t.Fatalf("Failed to write tsv: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and two rows, got %q", lines)
+	}
+	if want := "2\tline1\\nline2\\twith tab, comma \\\\ slash"; lines[2] != want {
+		t.Errorf("Expected escaped row %q, got %q", want, lines[2])
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	cols, _ := ParseColumns([]string{"id", "status", "name", "description"})
+	var buf bytes.Buffer
+	if err := writeMarkdown(&buf, tableTasks(), cols); err != nil {
+		t.Fatalf("Failed to write markdown: %v", err)
+	}
+
+	want := "| ID | STATUS | NAME | DESCRIPTION |\n" +
+		"| --: | --- | --- | --- |\n" +
+		"| 1 | OPEN | Plain |  |\n" +
+		"| 2 | WORKING | pipe \\| and \"quotes\" | line1<br>line2\twith tab, comma \\\\ slash |\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected markdown:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	cols, err := ParseColumns([]string{"ID", "updated_at", "due_at"})
+	if err != nil {
+		t.Fatalf("Failed to parse columns: %v", err)
+	}
+	if cols[0].Name != "id" || cols[1].Name != "updated" || cols[2].Name != "due" {
+		t.Errorf("Expected aliases to resolve, got %v %v %v", cols[0].Name, cols[1].Name, cols[2].Name)
+	}
+
+	if _, err := ParseColumns([]string{"owner"}); err == nil {
+		t.Error("Expected unknown column to be rejected")
+	}
+
+	cols, _ = ParseColumns(nil)
+	if len(cols) != len(DefaultColumns) {
+		t.Errorf("Expected default columns, got %d", len(cols))
+	}
+}
+
+func TestTextCell(t *testing.T) {
+	if got := textCell("a\tb\r\nc\nd"); got != "a b c d" {
+		t.Errorf("Expected tabs and line breaks to become spaces, got %q", got)
+	}
+}
+
+func TestProjectColumn(t *testing.T) {
+	cols, _ := ParseColumns([]string{"project", "id"})
+	tasks := []task.Task{{ID: 1, Project: "infra"}, {ID: 1}}
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, tasks, cols); err != nil {
+		t.Fatalf("Failed to write csv: %v", err)
+	}
+	if want := "project,id\ninfra,1\ndefault,1\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWriteStatusResults(t *testing.T) {
+	results := []task.StatusResult{
+		{ID: 1, OK: true, Task: &task.Task{ID: 1, Name: "Ship", Status: task.StatusDone}},
+		{ID: 9, Error: "task with ID 9 not found"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStatusResults(&buf, results, "json", Options{}); err != nil {
+		t.Fatalf("Failed to write json: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"ok": false,`) || !strings.Contains(buf.String(), `"error": "task with ID 9 not found"`) {
+		t.Errorf("Expected a result per ID, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteStatusResults(&buf, results, "csv", Options{}); err != nil {
+		t.Fatalf("Failed to write csv: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], "1,done") {
+		t.Errorf("Expected only the changed task, got %q", buf.String())
+	}
+}
@@ -0,0 +1,188 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// templateFormat renders every task with a user-supplied text/template
+type templateFormat struct {
+	tmpl *template.Template
+}
+
+// newTemplateFormat parses the template given inline, as in -o template='{{.ID}} {{.Name}}'
+func newTemplateFormat(text string) (Format, error) {
+	if text == "" {
+		return nil, fmt.Errorf("template is empty (use -o template='{{.ID}} {{.Name}}')")
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs(time.Now())).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return &templateFormat{tmpl: tmpl}, nil
+}
+
+// newTemplateFileFormat reads the template from a file, as in -o template-file=status.tmpl
+func newTemplateFileFormat(path string) (Format, error) {
+	if path == "" {
+		return nil, fmt.Errorf("template file path is required (use -o template-file=<path>)")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %v", err)
+	}
+	return newTemplateFormat(string(data))
+}
+
+// Tasks executes the template once per task, each on its own line
+func (f *templateFormat) Tasks(w io.Writer, tasks []task.Task, opts Options) error {
+	for i := range tasks {
+		if err := f.Task(w, &tasks[i], opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Task executes the template for a single task, ending the output with a newline
+func (f *templateFormat) Task(w io.Writer, t *task.Task, opts Options) error {
+	f.tmpl.Funcs(template.FuncMap{"status": statusFunc(opts.workflow(*t))})
+
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, t); err != nil {
+		return fmt.Errorf("failed to execute template: %v", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// templateFuncs are the helpers available in output templates; relative times are measured from now
+func templateFuncs(now time.Time) template.FuncMap {
+	return template.FuncMap{
+		// color "red" .Name wraps the text in an ANSI color (or "bold")
+		"color": func(name string, v interface{}) (string, error) {
+			code, ok := colorCodes[name]
+			if name == "bold" {
+				code, ok = "\033[1m", true
+			}
+			if !ok {
+				return "", fmt.Errorf("unknown color %q. Valid colors: %v", name, task.ColorNames)
+			}
+			return code + fmt.Sprint(v) + "\033[0m", nil
+		},
+		// status .Status renders the status in upper case in its workflow color
+		"status": statusFunc(defaultWorkflow),
+		// relative .UpdatedAt renders a time as "3h ago" or "in 2d"
+		"relative": func(v interface{}) (string, error) {
+			t, ok, err := templateTime(v)
+			if err != nil || !ok {
+				return "", err
+			}
+			return relativeTime(t, now), nil
+		},
+		// date .DueAt renders a time as YYYY-MM-DD
+		"date": func(v interface{}) (string, error) {
+			t, ok, err := templateTime(v)
+			if err != nil || !ok {
+				return "", err
+			}
+			return t.Format(task.DueDateLayout), nil
+		},
+		// pad 20 .Name pads the text with spaces to at least 20 characters
+		"pad": func(width int, v interface{}) string {
+			s := fmt.Sprint(v)
+			if n := len([]rune(s)); n < width {
+				s += strings.Repeat(" ", width-n)
+			}
+			return s
+		},
+		// padLeft 4 .ID right-aligns the text in at least 4 characters
+		"padLeft": func(width int, v interface{}) string {
+			s := fmt.Sprint(v)
+			if n := len([]rune(s)); n < width {
+				s = strings.Repeat(" ", width-n) + s
+			}
+			return s
+		},
+		// trunc 30 .Name shortens the text to at most 30 characters, ending in …
+		"trunc": func(width int, v interface{}) string {
+			runes := []rune(fmt.Sprint(v))
+			if len(runes) <= width {
+				return string(runes)
+			}
+			if width <= 1 {
+				return string(runes[:width])
+			}
+			return string(runes[:width-1]) + "…"
+		},
+		"upper": func(v interface{}) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"lower": func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+		"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+	}
+}
+
+// statusFunc returns the status template function for the statuses of workflow
+func statusFunc(workflow *task.Workflow) func(task.TaskStatus) string {
+	return func(status task.TaskStatus) string {
+		return getStatusColor(workflow, status) + strings.ToUpper(string(status)) + "\033[0m"
+	}
+}
+
+// templateTime accepts the time fields of a task; ok is false for an unset optional time
+func templateTime(v interface{}) (time.Time, bool, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, !t.IsZero(), nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, false, nil
+		}
+		return *t, true, nil
+	default:
+		return time.Time{}, false, fmt.Errorf("expected a time, got %T", v)
+	}
+}
+
+// relativeTime renders the distance between t and now, e.g. "5m ago" or "in 3d"
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		amount = fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		amount = fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 60*24*time.Hour:
+		amount = fmt.Sprintf("%dw", int(d.Hours()/(24*7)))
+	case d < 365*24*time.Hour:
+		amount = fmt.Sprintf("%dmo", int(d.Hours()/(24*30)))
+	default:
+		amount = fmt.Sprintf("%dy", int(d.Hours()/(24*365)))
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
@@ -0,0 +1,125 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 5, 18, 0, 0, 0, 0, time.UTC)
+	tk := &task.Task{
+		ID:        7,
+		Name:      "Rotate credentials",
+		Status:    task.StatusWorking,
+		Priority:  task.PriorityP1,
+		Tags:      []string{"ops", "security"},
+		CreatedAt: now.Add(-3 * time.Hour),
+		DueAt:     &due,
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{{.ID}} {{.Name}}", "7 Rotate credentials"},
+		{"{{.Name | trunc 10}}", "Rotate cr…"},
+		{"[{{.Priority | pad 4}}]", "[P1  ]"},
+		{"[{{.ID | padLeft 3}}]", "[  7]"},
+		{"{{relative .CreatedAt}}", "3h ago"},
+		{"{{relative .DueAt}}", "in 2d"},
+		{"{{date .DueAt}}", "2024-05-18"},
+		{"{{join \",\" .Tags}}", "ops,security"},
+		{"{{upper .Status}}", "WORKING"},
+		{"{{color \"red\" .ID}}", "\033[31m7\033[0m"},
+	}
+	for _, tt := range tests {
+		tmpl, err := newTemplateFormat(tt.template)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.template, err)
+		}
+		f := tmpl.(*templateFormat)
+		f.tmpl.Funcs(templateFuncs(now))
+
+		var buf bytes.Buffer
+		if err := f.tmpl.Execute(&buf, tk); err != nil {
+			t.Errorf("Template %q failed: %v", tt.template, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("Template %q = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	// Unset optional times render as nothing
+	var buf bytes.Buffer
+	f, _ := newTemplateFormat("{{relative .DueAt}}|{{date .DueAt}}")
+	if err := f.(*templateFormat).tmpl.Execute(&buf, &task.Task{}); err != nil || buf.String() != "|" {
+		t.Errorf("Expected empty output for unset due date, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestWriteTasks_Workflow(t *testing.T) {
+	w := &task.Workflow{Statuses: []task.StatusDef{
+		{Name: "todo", Kind: task.KindActive, Color: "cyan"},
+		{Name: "shipped", Kind: task.KindClosed, Color: "green"},
+	}}
+	yesterday := time.Now().AddDate(0, 0, -2)
+	tasks := []task.Task{{ID: 1, Name: "Release", Status: "shipped", DueAt: &yesterday}}
+
+	var buf bytes.Buffer
+	if err := WriteTasks(&buf, tasks, "normal", Options{Workflow: w}); err != nil {
+		t.Fatalf("Failed to write tasks: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\033[32mSHIPPED") || strings.Contains(buf.String(), "overdue") {
+		t.Errorf("Expected a green SHIPPED task that is not overdue, got %q", buf.String())
+	}
+
+	buf.Reset()
+	tasks[0].Status = "todo"
+	if err := WriteTasks(&buf, tasks, "template={{status .Status}}", Options{Workflow: w}); err != nil {
+		t.Fatalf("Failed to write tasks: %v", err)
+	}
+	if got := buf.String(); got != "\033[36mTODO\033[0m\n" {
+		t.Errorf("Expected TODO in the workflow's cyan, got %q", got)
+	}
+	// Tasks read from another store use that store's workflow
+	buf.Reset()
+	tasks = append(tasks, task.Task{ID: 1, Store: "api", Status: "todo"})
+	opts := Options{StoreWorkflows: map[string]*task.Workflow{"api": w}}
+	if err := WriteTasks(&buf, tasks, "template={{status .Status}}", opts); err != nil {
+		t.Fatalf("Failed to write tasks: %v", err)
+	}
+	if got := buf.String(); got != "\033[0mTODO\033[0m\n\033[36mTODO\033[0m\n" {
+		t.Errorf("Expected only the api task in its store's cyan, got %q", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, spec := range []string{"normal", "text", "json", "yaml", "template={{.ID}}"} {
+		if err := ValidateFormat(spec); err != nil {
+			t.Errorf("Expected %q to be valid: %v", spec, err)
+		}
+	}
+
+	for _, spec := range []string{"xml", "json=pretty", "template=", "template={{.ID", "template-file=/does/not/exist"} {
+		if err := ValidateFormat(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+
+	// Everything after the first = belongs to the template
+	f, err := ParseFormat("template={{if eq .ID 1}}a=b{{end}}")
+	if err != nil {
+		t.Fatalf("Failed to parse template with =: %v", err)
+	}
+	var buf bytes.Buffer
+	f.(*templateFormat).tmpl.Execute(&buf, &task.Task{ID: 1})
+	if !strings.Contains(buf.String(), "a=b") {
+		t.Errorf("Expected a=b, got %q", buf.String())
+	}
+}
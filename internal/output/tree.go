@@ -0,0 +1,131 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// TaskNode is a task together with its subtasks
+type TaskNode struct {
+	task.Task `yaml:",inline"`
+	Children  []TaskNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// BuildTree nests tasks under their parents. Tasks whose parent is not in
+// the list become roots. The order of the input is kept at every level.
+func BuildTree(tasks []task.Task) []TaskNode {
+	present := make(map[int]bool, len(tasks))
+	children := map[int][]task.Task{}
+	for _, t := range tasks {
+		present[t.ID] = true
+	}
+	for _, t := range tasks {
+		if t.Parent != 0 && present[t.Parent] {
+			children[t.Parent] = append(children[t.Parent], t)
+		}
+	}
+
+	var build func(t task.Task) TaskNode
+	build = func(t task.Task) TaskNode {
+		node := TaskNode{Task: t}
+		for _, child := range children[t.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	roots := []TaskNode{}
+	for _, t := range tasks {
+		if t.Parent == 0 || !present[t.Parent] {
+			roots = append(roots, build(t))
+		}
+	}
+	return roots
+}
+
+// WriteTaskTree writes tasks to w with subtasks nested under their parents
+func WriteTaskTree(w io.Writer, tasks []task.Task, format string, opts Options) error {
+	roots := BuildTree(tasks)
+	switch format {
+	case "json":
+		return formatJSON(w, roots)
+	case "yaml":
+		return formatYAML(w, roots)
+	case "text":
+		cols, err := ParseColumns(opts.Columns)
+		if err != nil {
+			return err
+		}
+		return formatTreeText(w, roots, cols)
+	case "normal":
+		return formatTreeNormal(w, roots, opts)
+	default:
+		// Formats without a tree layout get the tasks in tree order
+		return WriteTasks(w, flattenTree(roots), format, opts)
+	}
+}
+
+// flattenTree lists the tasks of the tree depth-first, parents before their children
+func flattenTree(nodes []TaskNode) []task.Task {
+	tasks := []task.Task{}
+	for _, node := range nodes {
+		tasks = append(tasks, node.Task)
+		tasks = append(tasks, flattenTree(node.Children)...)
+	}
+	return tasks
+}
+
+func formatTreeText(w io.Writer, roots []TaskNode, cols []Column) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, textHeader(cols))
+
+	var walk func(nodes []TaskNode, prefix string, root bool)
+	walk = func(nodes []TaskNode, prefix string, root bool) {
+		for i, node := range nodes {
+			connector, childPrefix := treeConnector(prefix, i == len(nodes)-1, root)
+			fmt.Fprintln(tw, textRow(node.Task, cols, connector))
+			walk(node.Children, childPrefix, false)
+		}
+	}
+	walk(roots, "", true)
+	return tw.Flush()
+}
+
+func formatTreeNormal(w io.Writer, roots []TaskNode, opts Options) error {
+	if len(roots) == 0 {
+		fmt.Fprintln(w, "No tasks found.")
+		return nil
+	}
+
+	var walk func(nodes []TaskNode, prefix string, root bool)
+	walk = func(nodes []TaskNode, prefix string, root bool) {
+		for i, node := range nodes {
+			connector, childPrefix := treeConnector(prefix, i == len(nodes)-1, root)
+			indent := childPrefix + "  "
+			if len(node.Children) > 0 {
+				indent = childPrefix + "│ "
+			}
+			printTaskNormalIndented(w, node.Task, connector, indent, opts)
+			walk(node.Children, childPrefix, false)
+			if root {
+				fmt.Fprintln(w)
+			}
+		}
+	}
+	walk(roots, "", true)
+	return nil
+}
+
+// treeConnector returns the prefix for a node's own line and the prefix for its children
+func treeConnector(prefix string, last, root bool) (string, string) {
+	if root {
+		return "", ""
+	}
+	if last {
+		return prefix + "└─ ", prefix + "   "
+	}
+	return prefix + "├─ ", prefix + "│  "
+}
@@ -0,0 +1,228 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// Fields lists the field names a query term can use
+var Fields = []string{"id", "status", "is", "tag", "priority", "due", "parent", "name", "description", "project"}
+
+// isValues lists the values accepted by the is: field
+var isValues = []string{"left", "active", "closed", "overdue", "today"}
+
+// relativeDays matches due offsets written without the leading +, e.g. due<7d
+var relativeDays = regexp.MustCompile(`^\d+[dw]$`)
+
+// field compiles a field<op>value term
+func (p *parser) field(name, op, value token) (predicate, error) {
+	switch strings.ToLower(name.text) {
+	case "id":
+		return intField(op, value, func(t task.Task) int { return t.ID })
+	case "parent":
+		return intField(op, value, func(t task.Task) int { return t.Parent })
+	case "status":
+		return statusField(op, value, p.workflow)
+	case "is":
+		return isField(op, value, p.now, p.workflow)
+	case "tag":
+		return tagField(op, value)
+	case "priority":
+		return priorityField(op, value)
+	case "due":
+		return dueField(op, value, p.now)
+	case "name":
+		return stringField(op, value, func(t task.Task) string { return t.Name })
+	case "description", "desc":
+		return stringField(op, value, func(t task.Task) string { return t.Description })
+	case "project":
+		return projectField(op, value)
+	default:
+		return nil, errorAt(name.pos, "unknown field %q. Valid fields: %v", name.text, Fields)
+	}
+}
+
+// compare applies a comparison operator to the result of comparing a task's value with the query's
+func compare(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "!=":
+		return c != 0
+	default:
+		return c == 0
+	}
+}
+
+// equalityOnly rejects ordering operators for fields that only support equality
+func equalityOnly(field string, op token) error {
+	switch op.text {
+	case ":", "=", "!=":
+		return nil
+	}
+	return errorAt(op.pos, "operator %s is not supported for %s (use :, = or !=)", op.text, field)
+}
+
+func intField(op, value token, get func(task.Task) int) (predicate, error) {
+	n, err := strconv.Atoi(value.text)
+	if err != nil {
+		return nil, errorAt(value.pos, "expected a number, got %s", value.describe())
+	}
+	return func(t task.Task) bool {
+		v := get(t)
+		switch {
+		case v < n:
+			return compare(op.text, -1)
+		case v > n:
+			return compare(op.text, 1)
+		}
+		return compare(op.text, 0)
+	}, nil
+}
+
+func statusField(op, value token, workflow *task.Workflow) (predicate, error) {
+	if err := equalityOnly("status", op); err != nil {
+		return nil, err
+	}
+
+	status := task.TaskStatus(strings.ToLower(value.text))
+	if workflow != nil {
+		if _, ok := workflow.Status(status); !ok {
+			return nil, errorAt(value.pos, "unknown status %s. Valid statuses: %v", value.describe(), workflow.StatusNames())
+		}
+	}
+
+	negate := op.text == "!="
+	return func(t task.Task) bool { return (t.Status == status) != negate }, nil
+}
+
+func isField(op, value token, now time.Time, workflow *task.Workflow) (predicate, error) {
+	if err := equalityOnly("is", op); err != nil {
+		return nil, err
+	}
+
+	var match predicate
+	switch strings.ToLower(value.text) {
+	case "left", "active":
+		match = func(t task.Task) bool { return workflow.IsActive(t.Status) }
+	case "closed":
+		match = func(t task.Task) bool { return workflow.IsClosed(t.Status) }
+	case "overdue":
+		match = func(t task.Task) bool { return workflow.Overdue(t, now) }
+	case "today":
+		match = func(t task.Task) bool { return workflow.DueToday(t, now) }
+	default:
+		return nil, errorAt(value.pos, "unknown value %s for is. Valid values: %v", value.describe(), isValues)
+	}
+
+	if op.text == "!=" {
+		return func(t task.Task) bool { return !match(t) }, nil
+	}
+	return match, nil
+}
+
+func tagField(op, value token) (predicate, error) {
+	if err := equalityOnly("tag", op); err != nil {
+		return nil, err
+	}
+
+	negate := op.text == "!="
+	return func(t task.Task) bool { return t.HasTag(value.text) != negate }, nil
+}
+
+// projectField matches the project a task was read from; project:default
+// matches the tasks of the default project
+func projectField(op, value token) (predicate, error) {
+	if err := equalityOnly("project", op); err != nil {
+		return nil, err
+	}
+
+	project := strings.ToLower(value.text)
+	if project == task.DefaultProject {
+		project = ""
+	}
+	negate := op.text == "!="
+	return func(t task.Task) bool { return (t.Project == project) != negate }, nil
+}
+
+// priorityField compares by urgency, so priority<=P1 matches P0 and P1.
+// Tasks without a priority only match priority:none and priority!=P<n>.
+func priorityField(op, value token) (predicate, error) {
+	priority, err := task.ParsePriority(value.text)
+	if err != nil {
+		return nil, errorAt(value.pos, "%v", err)
+	}
+
+	if priority == task.PriorityNone {
+		if err := equalityOnly("priority:none", op); err != nil {
+			return nil, err
+		}
+		negate := op.text == "!="
+		return func(t task.Task) bool { return (t.Priority == task.PriorityNone) != negate }, nil
+	}
+
+	return func(t task.Task) bool {
+		if t.Priority == task.PriorityNone {
+			return op.text == "!="
+		}
+		return compare(op.text, strings.Compare(string(t.Priority), string(priority)))
+	}, nil
+}
+
+// dueField compares due dates by day. The value is anything task.ParseDue
+// accepts, and offsets may omit the +, so due<7d means due within a week.
+// Tasks without a due date only match due:none and due!=<date>.
+func dueField(op, value token, now time.Time) (predicate, error) {
+	if strings.EqualFold(value.text, "none") {
+		if err := equalityOnly("due:none", op); err != nil {
+			return nil, err
+		}
+		negate := op.text == "!="
+		return func(t task.Task) bool { return (t.DueAt == nil) != negate }, nil
+	}
+
+	input := value.text
+	if relativeDays.MatchString(input) {
+		input = "+" + input
+	}
+	due, err := task.ParseDue(input, now)
+	if err != nil {
+		return nil, errorAt(value.pos, "%v", err)
+	}
+	day := dayOf(due)
+
+	return func(t task.Task) bool {
+		if t.DueAt == nil {
+			return op.text == "!="
+		}
+		return compare(op.text, dayOf(*t.DueAt).Compare(day))
+	}, nil
+}
+
+// dayOf strips the time of day so due dates compare by calendar day
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// stringField matches with : as a case-insensitive substring and = / != as case-insensitive equality
+func stringField(op, value token, get func(task.Task) string) (predicate, error) {
+	switch op.text {
+	case ":":
+		text := strings.ToLower(value.text)
+		return func(t task.Task) bool { return strings.Contains(strings.ToLower(get(t)), text) }, nil
+	case "=", "!=":
+		negate := op.text == "!="
+		return func(t task.Task) bool { return strings.EqualFold(get(t), value.text) != negate }, nil
+	}
+	return nil, errorAt(op.pos, "operator %s is not supported for text fields (use :, = or !=)", op.text)
+}
@@ -0,0 +1,99 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokNot
+)
+
+// token is a lexed piece of the query; pos is its 1-based column
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe names the token for error messages
+func (t token) describe() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return "\"" + t.text + "\""
+}
+
+// isKeyword reports whether the token is the bare word kw, ignoring case
+func (t token) isKeyword(kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+// wordBreaks are the characters that end a bare word
+const wordBreaks = `():=!<>"'`
+
+// lex splits the input into tokens
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := []token{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokNot, text: "-", pos: pos})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errorAt(pos, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokString, text: string(runes[i+1 : end]), pos: pos})
+			i = end + 1
+		case r == ':' || r == '=':
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: pos})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, errorAt(pos, "expected != (use not or - to negate)")
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: pos})
+			i += len(op)
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(wordBreaks, runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(runes[i:end]), pos: pos})
+			i = end
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
@@ -0,0 +1,224 @@
+// Package query implements the filter expressions accepted by uni list, e.g.
+//
+//	status:working and tag:backend and due<7d or priority:P0
+//
+// A term is either field<op>value or a bare word, which matches the name or
+// description. Terms are combined with and (also implied by juxtaposition),
+// or and not (or a leading -), and grouped with parentheses; and binds
+// tighter than or.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// Error is a syntax or value error in a query
+type Error struct {
+	// Column is the 1-based position in the query the error refers to
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s", e.Column, e.Msg)
+}
+
+func errorAt(column int, format string, args ...interface{}) *Error {
+	return &Error{Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// predicate reports whether a task matches part of a query
+type predicate func(t task.Task) bool
+
+// Query is a parsed filter expression
+type Query struct {
+	input string
+	match predicate
+}
+
+// Parse parses a query; relative dates such as due<7d are resolved against the current time
+func Parse(input string) (*Query, error) {
+	return ParseAt(input, time.Now())
+}
+
+// ParseAt parses a query, resolving relative dates against now. An empty query matches every task.
+// Statuses are those of task.DefaultWorkflow.
+func ParseAt(input string, now time.Time) (*Query, error) {
+	return ParseWorkflow(input, now, task.DefaultWorkflow())
+}
+
+// ParseWorkflow parses a query like ParseAt, checking statuses and resolving
+// is:left and is:closed against workflow w; a nil w only checks the syntax, see Check
+func ParseWorkflow(input string, now time.Time, w *task.Workflow) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{input: input, match: func(task.Task) bool { return true }}
+	if len(tokens) == 1 {
+		return q, nil
+	}
+
+	p := &parser{tokens: tokens, now: now, workflow: w}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, errorAt(tok.pos, "unexpected %s", tok.describe())
+	}
+
+	q.match = match
+	return q, nil
+}
+
+// Check checks the syntax of a query, accepting any status. It is for queries
+// that are run later by stores with different workflows.
+func Check(input string) error {
+	_, err := ParseWorkflow(input, time.Now(), nil)
+	return err
+}
+
+// Match reports whether the task matches the query
+func (q *Query) Match(t task.Task) bool {
+	return q.match(t)
+}
+
+// String returns the query as it was written
+func (q *Query) String() string {
+	return q.input
+}
+
+// parser is a recursive descent parser over the lexed tokens
+type parser struct {
+	tokens   []token
+	i        int
+	now      time.Time
+	workflow *task.Workflow
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+// parseOr parses: and-expr ("or" and-expr)*
+func (p *parser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t task.Task) bool { return l(t) || right(t) }
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary (["and"] unary)*
+func (p *parser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.isKeyword("and") {
+			p.next()
+		} else if !p.startsTerm(tok) {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t task.Task) bool { return l(t) && right(t) }
+	}
+}
+
+// startsTerm reports whether tok can begin another term of an implicit and
+func (p *parser) startsTerm(tok token) bool {
+	switch tok.kind {
+	case tokWord:
+		return !tok.isKeyword("or")
+	case tokString, tokLParen, tokNot:
+		return true
+	}
+	return false
+}
+
+// parseUnary parses: ("not" | "-") unary | primary
+func (p *parser) parseUnary() (predicate, error) {
+	if tok := p.peek(); tok.kind == tokNot || (tok.isKeyword("not") && p.tokens[p.i+1].kind != tokOp) {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t task.Task) bool { return !inner(t) }, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: "(" or-expr ")" | field op value | word | string
+func (p *parser) parsePrimary() (predicate, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, errorAt(closing.pos, "expected ) to close the ( at column %d, got %s", tok.pos, closing.describe())
+		}
+		return inner, nil
+	case tokWord:
+		if p.peek().kind == tokOp {
+			op := p.next()
+			value := p.next()
+			if value.kind != tokWord && value.kind != tokString {
+				return nil, errorAt(value.pos, "expected a value after %s%s, got %s", tok.text, op.text, value.describe())
+			}
+			return p.field(tok, op, value)
+		}
+		if tok.isKeyword("and") || tok.isKeyword("or") {
+			return nil, errorAt(tok.pos, "unexpected %s", tok.describe())
+		}
+		return textMatch(tok.text), nil
+	case tokString:
+		return textMatch(tok.text), nil
+	default:
+		return nil, errorAt(tok.pos, "unexpected %s", tok.describe())
+	}
+}
+
+// textMatch matches tasks whose name or description contains text, ignoring case
+func textMatch(text string) predicate {
+	text = strings.ToLower(text)
+	return func(t task.Task) bool {
+		return strings.Contains(strings.ToLower(t.Name), text) ||
+			strings.Contains(strings.ToLower(t.Description), text)
+	}
+}
@@ -0,0 +1,131 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+func TestQuery_Match(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	day := func(d int) *time.Time {
+		due := time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+		return &due
+	}
+	tasks := []task.Task{
+		{ID: 1, Name: "Fix login", Status: task.StatusWorking, Tags: []string{"backend"}, DueAt: day(18), Priority: task.PriorityP2},
+		{ID: 2, Name: "Outage", Status: task.StatusOpen, Priority: task.PriorityP0},
+		{ID: 3, Name: "Docs", Description: "Write the login guide", Status: task.StatusDone, Tags: []string{"docs"}, DueAt: day(10)},
+		{ID: 4, Name: "Refactor", Status: task.StatusOpen, Tags: []string{"backend"}, DueAt: day(30), Parent: 1, Project: "infra"},
+		{ID: 5, Name: "Late", Status: task.StatusBlocked, DueAt: day(14), Priority: task.PriorityP1},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{1, 2, 3, 4, 5}},
+		{"status:working and tag:backend and due<7d or priority:P0", []int{1, 2}},
+		{"tag:backend", []int{1, 4}},
+		{"tag:backend due<7d", []int{1}},
+		{"tag:backend and not due<7d", []int{4}},
+		{"-tag:backend", []int{2, 3, 5}},
+		{"status!=open", []int{1, 3, 5}},
+		{"is:left", []int{1, 2, 4, 5}},
+		{"is:closed", []int{3}},
+		{"is:overdue", []int{5}},
+		{"priority<=P1", []int{2, 5}},
+		{"priority:none", []int{3, 4}},
+		{"due:none", []int{2}},
+		{"due:2024-05-18", []int{1}},
+		{"due>=fri", []int{1, 4}},
+		{"id>3", []int{4, 5}},
+		{"parent:1", []int{4}},
+		{"parent=0 and (tag:docs or priority:P0)", []int{2, 3}},
+		{"login", []int{1, 3}},
+		{`"fix login"`, []int{1}},
+		{"name:LOG", []int{1}},
+		{"name=docs", []int{3}},
+		{"description:guide", []int{3}},
+		{"project:infra", []int{4}},
+		{"project:default", []int{1, 2, 3, 5}},
+		{"project!=infra", []int{1, 2, 3, 5}},
+		{"STATUS:Working OR Is:Closed", []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseAt(tt.query, now)
+		if err != nil {
+			t.Errorf("ParseAt(%q) failed: %v", tt.query, err)
+			continue
+		}
+
+		got := []int{}
+		for _, tk := range tasks {
+			if q.Match(tk) {
+				got = append(got, tk.ID)
+			}
+		}
+		if !equalIDs(got, tt.want) {
+			t.Errorf("Query %q matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query  string
+		column int
+	}{
+		{"status:wrk", 8},
+		{"owner:me", 1},
+		{"(tag:a or tag:b", 16},
+		{"tag:a or", 9},
+		{"tag:a )", 7},
+		{"tag<a", 4},
+		{"priority:P7", 10},
+		{"due<later", 5},
+		{"id:abc", 4},
+		{`name:"unclosed`, 6},
+		{"tag:", 5},
+		{"tag ! x", 5},
+		{"and tag:x", 1},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Errorf("Parse(%q): expected *Error, got %v", tt.query, err)
+			continue
+		}
+		if qerr.Column != tt.column {
+			t.Errorf("Parse(%q): expected error at column %d, got %d (%v)", tt.query, tt.column, qerr.Column, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check("status:review and is:left"); err != nil {
+		t.Errorf("Expected any status to pass the syntax check: %v", err)
+	}
+	var qerr *Error
+	if err := Check("status:review and"); !errors.As(err, &qerr) || qerr.Column != 18 {
+		t.Errorf("Expected a syntax error at column 18, got %v", err)
+	}
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
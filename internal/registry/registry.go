@@ -0,0 +1,188 @@
+// Package registry remembers the data directories uni has been used with, so
+// their tasks can be listed together and addressed as name#id.
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mad01/uni/internal/config"
+	"github.com/mad01/uni/internal/task"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the registry file inside the global data directory
+const FileName = "stores.yaml"
+
+// LockName is the lock file that serializes changes to the registry file
+const LockName = "stores.lock"
+
+// GlobalName is the name the global data directory is registered under
+const GlobalName = "global"
+
+// Store is a registered data directory
+type Store struct {
+	// Name addresses the store, e.g. in api#12; it defaults to the repository name
+	Name string `yaml:"name" json:"name"`
+	// Dir is the absolute path of the data directory
+	Dir string `yaml:"dir" json:"dir"`
+	// Root is the git repository the data directory belongs to, if any
+	Root string `yaml:"root,omitempty" json:"root,omitempty"`
+}
+
+// Registry is the list of known stores, kept in ~/.uni/stores.yaml
+type Registry struct {
+	path   string
+	Stores []Store `yaml:"stores"`
+}
+
+// Path returns the path of the registry file
+func Path() (string, error) {
+	dir, err := config.GlobalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the registry file at path; a missing file yields an empty registry
+func Load(path string) (*Registry, error) {
+	r := &Registry{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return r, nil
+}
+
+// Update loads the registry at path, applies fn and saves the result if fn
+// reports a change. The lock file next to path is held throughout, so
+// concurrent updates do not overwrite each other.
+func Update(path string, fn func(r *Registry) (bool, error)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := task.LockFile(filepath.Join(filepath.Dir(path), LockName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r, err := Load(path)
+	if err != nil {
+		return err
+	}
+	changed, err := fn(r)
+	if err != nil || !changed {
+		return err
+	}
+	return r.Save()
+}
+
+// Save writes the registry back to its file; use Update to change a registry
+// other processes may change too
+func (r *Registry) Save() error {
+	sort.Slice(r.Stores, func(i, j int) bool { return r.Stores[i].Name < r.Stores[j].Name })
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return task.WriteFileAtomic(r.path, data, 0644)
+}
+
+// Lookup returns the store registered under name
+func (r *Registry) Lookup(name string) (Store, bool) {
+	for _, s := range r.Stores {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Store{}, false
+}
+
+// Find returns the store registered for the data directory dir
+func (r *Registry) Find(dir string) (Store, bool) {
+	for _, s := range r.Stores {
+		if s.Dir == dir {
+			return s, true
+		}
+	}
+	return Store{}, false
+}
+
+// Add registers the data directory dir of repository root (empty if none) and
+// reports whether it was new. The name is derived from the repository or
+// directory name and made unique with a -2, -3, ... suffix.
+func (r *Registry) Add(dir, root string) (Store, bool) {
+	if s, ok := r.Find(dir); ok {
+		return s, false
+	}
+
+	base := nameFor(dir, root)
+	name := base
+	for n := 2; ; n++ {
+		if _, taken := r.Lookup(name); !taken {
+			break
+		}
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	s := Store{Name: name, Dir: dir, Root: root}
+	r.Stores = append(r.Stores, s)
+	return s, true
+}
+
+// Remove drops the store registered under name and reports whether there was one
+func (r *Registry) Remove(name string) bool {
+	for i, s := range r.Stores {
+		if s.Name == name {
+			r.Stores = append(r.Stores[:i], r.Stores[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// nameFor derives a store name from its repository, or else the directory
+// holding the data directory
+func nameFor(dir, root string) string {
+	if global, err := config.GlobalDir(); err == nil && dir == global {
+		return GlobalName
+	}
+
+	base := filepath.Base(dir)
+	if root != "" {
+		base = filepath.Base(root)
+	} else if base == task.DataDirName {
+		base = filepath.Base(filepath.Dir(dir))
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, base)
+	name = strings.Trim(name, "-.")
+	if name == "" {
+		return "store"
+	}
+	return name
+}
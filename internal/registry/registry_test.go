@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_AddAndSave(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".uni", FileName)
+
+	r, err := Load(path)
+	if err != nil || len(r.Stores) != 0 {
+		t.Fatalf("Expected an empty registry, got %+v (%v)", r, err)
+	}
+
+	cases := []struct {
+		dir, root, want string
+		added           bool
+	}{
+		{"/src/api/.uni", "/src/api", "api", true},
+		{"/src/api/.uni", "/src/api", "api", false},
+		{"/work/api/.uni", "/work/api", "api-2", true},
+		{"/notes/My Notes/.uni", "", "my-notes", true},
+		{"/tmp/custom-dir", "", "custom-dir", true},
+		{filepath.Join(home, ".uni"), "", GlobalName, true},
+	}
+	for _, c := range cases {
+		s, added := r.Add(c.dir, c.root)
+		if s.Name != c.want || added != c.added {
+			t.Errorf("Add(%s): expected %s (added %v), got %s (added %v)", c.dir, c.want, c.added, s.Name, added)
+		}
+	}
+
+	if err := r.Save(); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if s, ok := loaded.Lookup("api-2"); !ok || s.Dir != "/work/api/.uni" || s.Root != "/work/api" {
+		t.Errorf("Expected api-2 to survive a round trip, got %+v", s)
+	}
+	if len(loaded.Stores) != 5 || loaded.Stores[0].Name != "api" {
+		t.Errorf("Expected 5 stores sorted by name, got %+v", loaded.Stores)
+	}
+
+	if !loaded.Remove("api") || loaded.Remove("api") {
+		t.Error("Expected api to be removed once")
+	}
+	if _, ok := loaded.Lookup("api"); ok {
+		t.Error("Expected api to be gone")
+	}
+}
+
+func TestUpdate_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- Update(path, func(r *Registry) (bool, error) {
+				_, added := r.Add(fmt.Sprintf("/src/repo-%d/.uni", i), "")
+				return added, nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to update: %v", err)
+		}
+	}
+
+	r, err := Load(path)
+	if err != nil || len(r.Stores) != 20 {
+		t.Errorf("Expected every registration to survive, got %d (%v)", len(r.Stores), err)
+	}
+}
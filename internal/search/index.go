@@ -0,0 +1,114 @@
+// Package search implements full-text search over task names, descriptions
+// and notes (the reasons recorded with status changes).
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// Doc holds the tokens of one task, per field, in the order they appear
+type Doc struct {
+	Name        []string
+	Description []string
+	Notes       []string
+}
+
+// fields returns the token lists of the doc in the order of fieldWeights
+func (d *Doc) fields() [][]string {
+	return [][]string{d.Name, d.Description, d.Notes}
+}
+
+// fieldWeights rank a match in the name above one in the description or notes
+var fieldWeights = []float64{3, 1.5, 1}
+
+// Index is an inverted index from words to the tasks containing them. It is
+// built in memory for each search; nothing is kept on disk.
+type Index struct {
+	Docs  map[int]*Doc
+	Terms map[string][]int
+}
+
+// NewIndex returns an index of tasks
+func NewIndex(tasks ...task.Task) *Index {
+	idx := &Index{Docs: map[int]*Doc{}, Terms: map[string][]int{}}
+	for _, t := range tasks {
+		idx.Add(t)
+	}
+	return idx
+}
+
+// Add indexes the task, replacing any previous version of it
+func (idx *Index) Add(t task.Task) {
+	idx.Remove(t.ID)
+
+	notes := []string{}
+	for _, tr := range t.History {
+		notes = append(notes, tokenize(tr.Reason)...)
+	}
+	doc := &Doc{
+		Name:        tokenize(t.Name),
+		Description: tokenize(t.Description),
+		Notes:       notes,
+	}
+	idx.Docs[t.ID] = doc
+
+	seen := map[string]bool{}
+	for _, field := range doc.fields() {
+		for _, word := range field {
+			if !seen[word] {
+				seen[word] = true
+				idx.Terms[word] = insertID(idx.Terms[word], t.ID)
+			}
+		}
+	}
+}
+
+// Remove drops a task from the index
+func (idx *Index) Remove(id int) {
+	doc, ok := idx.Docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.Docs, id)
+
+	for _, field := range doc.fields() {
+		for _, word := range field {
+			ids := removeID(idx.Terms[word], id)
+			if len(ids) == 0 {
+				delete(idx.Terms, word)
+			} else {
+				idx.Terms[word] = ids
+			}
+		}
+	}
+}
+
+// tokenize lowercases text and splits it into words of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func insertID(ids []int, id int) []int {
+	i := sort.SearchInts(ids, id)
+	if i < len(ids) && ids[i] == id {
+		return ids
+	}
+	ids = append(ids, 0)
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+	return ids
+}
+
+func removeID(ids []int, id int) []int {
+	i := sort.SearchInts(ids, id)
+	if i == len(ids) || ids[i] != id {
+		return ids
+	}
+	return append(ids[:i], ids[i+1:]...)
+}
@@ -0,0 +1,240 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+// clause is one search term: a single word or a quoted phrase. With prefix set
+// the last word matches any word starting with it.
+type clause struct {
+	words  []string
+	prefix bool
+}
+
+// Query is a parsed search: every clause must match somewhere in the task
+type Query struct {
+	clauses []clause
+}
+
+// ParseQuery parses search terms. Words are matched whole, "quoted phrases"
+// must appear in order, and a trailing * matches any word starting with the
+// text before it (redis*).
+func ParseQuery(input string) (*Query, error) {
+	q := &Query{}
+
+	rest := input
+	for {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			break
+		}
+
+		var text string
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase in search: %s", rest)
+			}
+			text, rest = rest[1:end+1], rest[end+2:]
+			if strings.HasPrefix(rest, "*") {
+				text, rest = text+"*", rest[1:]
+			}
+		} else {
+			end := strings.IndexAny(rest, " \t\"")
+			if end < 0 {
+				end = len(rest)
+			}
+			text, rest = rest[:end], rest[end:]
+		}
+
+		c := clause{prefix: strings.HasSuffix(text, "*"), words: tokenize(text)}
+		if len(c.words) > 0 {
+			q.clauses = append(q.clauses, c)
+		}
+	}
+
+	if len(q.clauses) == 0 {
+		return nil, fmt.Errorf("search terms are required")
+	}
+	return q, nil
+}
+
+// Result is a task matching a search, best first
+type Result struct {
+	Task  task.Task `json:"task" yaml:"task"`
+	Score float64   `json:"score" yaml:"score"`
+	// Terms are the indexed words that matched, for highlighting
+	Terms []string `json:"terms" yaml:"terms"`
+}
+
+// Search ranks the given tasks against the query. Only tasks in the index and
+// in tasks are considered, so callers can narrow the search by passing a
+// filtered list. Scores are TF-IDF weighted by field and field length.
+func (idx *Index) Search(q *Query, tasks []task.Task) []Result {
+	candidates := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		if _, ok := idx.Docs[t.ID]; ok {
+			candidates[t.ID] = t
+		}
+	}
+
+	scores := map[int]float64{}
+	terms := map[int]map[string]bool{}
+	for id := range candidates {
+		terms[id] = map[string]bool{}
+	}
+
+	for _, c := range q.clauses {
+		matched := map[int]bool{}
+		counts := map[int][]int{}
+		for _, id := range idx.lookup(c) {
+			if _, ok := candidates[id]; !ok {
+				continue
+			}
+			doc := idx.Docs[id]
+			for i, field := range doc.fields() {
+				n, words := matchField(field, c)
+				if n == 0 {
+					continue
+				}
+				if counts[id] == nil {
+					counts[id] = make([]int, len(fieldWeights))
+				}
+				counts[id][i] = n
+				matched[id] = true
+				for _, w := range words {
+					terms[id][w] = true
+				}
+			}
+		}
+
+		// Every clause must match; drop the candidates this one missed
+		for id := range candidates {
+			if !matched[id] {
+				delete(candidates, id)
+			}
+		}
+		if len(candidates) == 0 {
+			return []Result{}
+		}
+
+		idf := math.Log(1 + float64(len(idx.Docs))/float64(len(matched)))
+		for id, fieldCounts := range counts {
+			doc := idx.Docs[id]
+			for i, field := range doc.fields() {
+				if fieldCounts[i] == 0 {
+					continue
+				}
+				tf := 1 + math.Log(float64(fieldCounts[i]))
+				scores[id] += fieldWeights[i] * tf * idf / math.Sqrt(float64(len(field)))
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(candidates))
+	for id, t := range candidates {
+		words := make([]string, 0, len(terms[id]))
+		for w := range terms[id] {
+			words = append(words, w)
+		}
+		sort.Strings(words)
+		results = append(results, Result{Task: t, Score: math.Round(scores[id]*1000) / 1000, Terms: words})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.ID < results[j].Task.ID
+	})
+	return results
+}
+
+// lookup returns the IDs of the tasks that contain every word of the clause
+func (idx *Index) lookup(c clause) []int {
+	var ids []int
+	for i, word := range c.words {
+		var found []int
+		if c.prefix && i == len(c.words)-1 {
+			for term, termIDs := range idx.Terms {
+				if strings.HasPrefix(term, word) {
+					found = unionIDs(found, termIDs)
+				}
+			}
+		} else {
+			found = idx.Terms[word]
+		}
+
+		if i == 0 {
+			ids = found
+		} else {
+			ids = intersectIDs(ids, found)
+		}
+	}
+	return ids
+}
+
+// matchField counts the occurrences of the clause in a field's tokens and returns the words matched
+func matchField(tokens []string, c clause) (int, []string) {
+	count := 0
+	words := []string{}
+	for i := 0; i+len(c.words) <= len(tokens); i++ {
+		ok := true
+		for j, word := range c.words {
+			token := tokens[i+j]
+			last := j == len(c.words)-1
+			if token != word && !(c.prefix && last && strings.HasPrefix(token, word)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			count++
+			words = append(words, tokens[i:i+len(c.words)]...)
+		}
+	}
+	return count, words
+}
+
+func unionIDs(a, b []int) []int {
+	out := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			out = append(out, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			out = append(out, b[j])
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+func intersectIDs(a, b []int) []int {
+	out := []int{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mad01/uni/internal/task"
+)
+
+func searchTasks() []task.Task {
+	now := time.Now()
+	return []task.Task{
+		{ID: 1, Name: "Redis connection pool exhausted", Description: "The redis cluster drops connections", UpdatedAt: now},
+		{ID: 2, Name: "Upgrade postgres", Description: "Move off the old connection pooler", UpdatedAt: now},
+		{ID: 3, Name: "Deploy api", UpdatedAt: now, History: []task.Transition{
+			{From: task.StatusOpen, To: task.StatusBlocked, Reason: "waiting for redis migration"},
+		}},
+		{ID: 4, Name: "Pool party", Description: "Not about connections at all", UpdatedAt: now},
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	tasks := searchTasks()
+	idx := NewIndex(tasks...)
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"redis", []int{1, 3}},
+		{"REDIS migration", []int{3}},
+		{`"connection pool"`, []int{1}},
+		{`"pool connection"`, []int{}},
+		{"pool*", []int{4, 1, 2}},
+		{"conn* pool*", []int{1, 4, 2}},
+		{"kafka", []int{}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) failed: %v", tt.query, err)
+		}
+
+		results := idx.Search(q, tasks)
+		got := []int{}
+		for _, r := range results {
+			got = append(got, r.Task.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// Only the tasks passed in are searched
+	q, _ := ParseQuery("redis")
+	if results := idx.Search(q, tasks[2:]); len(results) != 1 || results[0].Task.ID != 3 {
+		t.Errorf("Expected only task 3 when searching a subset, got %v", results)
+	}
+
+	// The matched words are reported for highlighting
+	q, _ = ParseQuery("pool*")
+	results := idx.Search(q, tasks[1:2])
+	if len(results) != 1 || len(results[0].Terms) != 1 || results[0].Terms[0] != "pooler" {
+		t.Errorf("Expected pooler to be the matched term, got %v", results)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, input := range []string{"", "  ", `"unterminated`, "***"} {
+		if _, err := ParseQuery(input); err == nil {
+			t.Errorf("Expected ParseQuery(%q) to fail", input)
+		}
+	}
+}
+
+func TestIndex_AddAndRemove(t *testing.T) {
+	tasks := searchTasks()
+	idx := NewIndex(tasks...)
+
+	// An edited task is re-indexed and a deleted one dropped
+	tasks[0].Name = "Memcached eviction storm"
+	idx.Add(tasks[0])
+	idx.Remove(4)
+	if _, ok := idx.Docs[4]; ok {
+		t.Error("Expected deleted task to be dropped")
+	}
+	q, _ := ParseQuery("memcached")
+	if results := idx.Search(q, tasks); len(results) != 1 {
+		t.Errorf("Expected edited task to be found by its new name, got %v", results)
+	}
+	q, _ = ParseQuery("exhausted")
+	if results := idx.Search(q, tasks); len(results) != 0 {
+		t.Errorf("Expected old name to be gone from the index, got %v", results)
+	}
+}
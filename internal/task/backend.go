@@ -0,0 +1,178 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultBackend is the backend used when none is configured
+const DefaultBackend = "json"
+
+// Filter selects which tasks a backend returns from List
+type Filter struct {
+	// Statuses limits the result to tasks in one of these statuses (empty means any)
+	Statuses []TaskStatus
+	// BlockedBy limits the result to tasks blocked by the task with this ID (0 means any)
+	BlockedBy int
+	// Parent limits the result to direct subtasks of the task with this ID (0 means any)
+	Parent int
+	// Tags limits the result to tasks carrying all of these tags
+	Tags []string
+	// Match, if set, must also accept the task, e.g. a parsed query
+	Match func(t Task) bool
+}
+
+// Matches reports whether the task passes the filter
+func (f Filter) Matches(t Task) bool {
+	if f.BlockedBy != 0 && !containsID(t.BlockedBy, f.BlockedBy) {
+		return false
+	}
+	if f.Parent != 0 && t.Parent != f.Parent {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	if f.Match != nil && !f.Match(t) {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, status := range f.Statuses {
+		if t.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Reader is the read side shared by backends and transactions
+type Reader interface {
+	// Get returns a copy of the task with the given ID
+	Get(id int) (*Task, error)
+	// List returns the tasks matching the filter in no particular order
+	List(filter Filter) ([]Task, error)
+}
+
+// Tx is a read-modify-write view of a backend handed to Mutate
+type Tx interface {
+	Reader
+	// Put inserts the task, or replaces the stored task with the same ID
+	Put(task *Task) error
+	// NextID returns the ID the next new task should get
+	NextID() (int, error)
+	// Delete removes the task with the given ID
+	Delete(id int) error
+}
+
+// Backend is the storage layer behind a TaskStore
+type Backend interface {
+	Reader
+	// Load returns every stored task
+	Load() ([]Task, error)
+	// Save replaces every stored task with the given tasks
+	Save(tasks []Task) error
+	// Mutate runs fn in a transaction; changes are persisted only if fn returns nil
+	Mutate(fn func(tx Tx) error) error
+	// Close releases any resources held by the backend
+	Close() error
+}
+
+// BackendFactory opens a backend rooted at a data directory
+type BackendFactory func(dataDir string) (Backend, error)
+
+var backends = map[string]BackendFactory{
+	"json": func(dataDir string) (Backend, error) {
+		return NewJSONBackend(dataDir), nil
+	},
+	"memory": func(dataDir string) (Backend, error) {
+		return NewMemoryBackend(), nil
+	},
+	"sqlite": NewSQLiteBackend,
+	"events": func(dataDir string) (Backend, error) {
+		return NewEventBackend(dataDir), nil
+	},
+}
+
+// RegisterBackend makes a backend available by name to OpenBackend
+func RegisterBackend(name string, factory BackendFactory) {
+	backends[name] = factory
+}
+
+// BackendNames returns the names of all registered backends
+func BackendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenBackend opens the named backend rooted at dataDir
+func OpenBackend(name, dataDir string) (Backend, error) {
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown store backend: %s. Valid backends: %v", name, BackendNames())
+	}
+	return factory(dataDir)
+}
+
+// sliceTx implements Tx on top of a slice of tasks held in memory
+type sliceTx struct {
+	tasks []Task
+}
+
+func (tx *sliceTx) Get(id int) (*Task, error) {
+	for _, task := range tx.tasks {
+		if task.ID == id {
+			task = task.clone()
+			return &task, nil
+		}
+	}
+	return nil, &NotFoundError{ID: id}
+}
+
+func (tx *sliceTx) List(filter Filter) ([]Task, error) {
+	tasks := []Task{}
+	for _, task := range tx.tasks {
+		if filter.Matches(task) {
+			tasks = append(tasks, task.clone())
+		}
+	}
+	return tasks, nil
+}
+
+func (tx *sliceTx) Put(task *Task) error {
+	for i := range tx.tasks {
+		if tx.tasks[i].ID == task.ID {
+			tx.tasks[i] = task.clone()
+			return nil
+		}
+	}
+	tx.tasks = append(tx.tasks, task.clone())
+	return nil
+}
+
+func (tx *sliceTx) Delete(id int) error {
+	for i := range tx.tasks {
+		if tx.tasks[i].ID == id {
+			tx.tasks = append(tx.tasks[:i], tx.tasks[i+1:]...)
+			return nil
+		}
+	}
+	return &NotFoundError{ID: id}
+}
+
+func (tx *sliceTx) NextID() (int, error) {
+	maxID := 0
+	for _, task := range tx.tasks {
+		if task.ID > maxID {
+			maxID = task.ID
+		}
+	}
+	return maxID + 1, nil
+}
@@ -0,0 +1,182 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryBackend_TaskStore(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+
+	task, err := store.AddTask("Memory Task", "")
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	if _, err := store.UpdateTaskStatus(task.ID, StatusDone); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+
+	closed := store.ListTasksWithFilter(false, true)
+	if len(closed) != 1 || closed[0].ID != task.ID {
+		t.Errorf("Expected task %d to be closed, got %v", task.ID, closed)
+	}
+}
+
+func TestMemoryBackend_MutateRollback(t *testing.T) {
+	backend := NewMemoryBackend()
+	store := NewTaskStoreWithBackend(backend)
+
+	if _, err := store.AddTask("Keep", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	errBoom := errors.New("boom")
+	err := backend.Mutate(func(tx Tx) error {
+		if err := tx.Put(&Task{ID: 2, Name: "Discard"}); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected mutate error to be returned, got %v", err)
+	}
+
+	tasks, err := backend.Load()
+	if err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("Expected failed mutation to be discarded, got %d tasks", len(tasks))
+	}
+}
+
+func TestMemoryBackend_ListReturnsCopies(t *testing.T) {
+	backend := NewMemoryBackend()
+	store := NewTaskStoreWithBackend(backend)
+	if _, err := store.CreateTask(TaskSpec{Name: "Tagged", Tags: []string{"api"}}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	listed, err := backend.List(Filter{})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	listed[0].Tags[0] = "changed"
+
+	stored, _ := backend.Get(1)
+	if stored.Tags[0] != "api" {
+		t.Errorf("Expected editing a listed task to leave the store alone, got tag %s", stored.Tags[0])
+	}
+}
+
+func TestOpenBackend(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for _, name := range []string{"json", "memory"} {
+		if _, err := OpenBackend(name, tempDir); err != nil {
+			t.Errorf("Expected backend %s to open, got %v", name, err)
+		}
+	}
+
+	if _, err := OpenBackend("nope", tempDir); err == nil {
+		t.Error("Expected error when opening unknown backend")
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	filter := DefaultWorkflow().StatusFilter(true, false)
+
+	if !filter.Matches(Task{Status: StatusWorking}) {
+		t.Error("Expected working task to match left filter")
+	}
+	if filter.Matches(Task{Status: StatusDone}) {
+		t.Error("Expected done task to not match left filter")
+	}
+	if !(Filter{}).Matches(Task{Status: StatusDone}) {
+		t.Error("Expected empty filter to match everything")
+	}
+
+	filter.Match = func(t Task) bool { return t.Name == "wanted" }
+	if filter.Matches(Task{Status: StatusWorking, Name: "other"}) {
+		t.Error("Expected Match to reject the task")
+	}
+	if !filter.Matches(Task{Status: StatusWorking, Name: "wanted"}) {
+		t.Error("Expected task accepted by Match and statuses to match")
+	}
+}
+
+func TestBackends_Conformance(t *testing.T) {
+	for _, name := range BackendNames() {
+		t.Run(name, func(t *testing.T) {
+			backend, err := OpenBackend(name, t.TempDir())
+			if err != nil {
+				t.Fatalf("Failed to open backend: %v", err)
+			}
+			defer backend.Close()
+
+			store := NewTaskStoreWithBackend(backend)
+			for _, n := range []string{"One", "Two", "Three"} {
+				if _, err := store.AddTask(n, ""); err != nil {
+					t.Fatalf("Failed to add task: %v", err)
+				}
+			}
+
+			if _, err := store.UpdateTaskStatus(2, StatusDone); err != nil {
+				t.Fatalf("Failed to update task status: %v", err)
+			}
+
+			left, err := store.FindTasks(DefaultWorkflow().StatusFilter(true, false))
+			if err != nil {
+				t.Fatalf("Failed to list tasks: %v", err)
+			}
+			if len(left) != 2 || left[0].ID != 1 || left[1].ID != 3 {
+				t.Errorf("Expected tasks 1 and 3 to be left, got %v", left)
+			}
+
+			got, err := store.GetTask(2)
+			if err != nil {
+				t.Fatalf("Failed to get task: %v", err)
+			}
+			if got.Status != StatusDone || got.Name != "Two" {
+				t.Errorf("Expected task 2 to be done, got %+v", got)
+			}
+
+			if _, err := store.GetTask(42); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected ErrNotFound when getting non-existent task, got %v", err)
+			}
+
+			if err := store.DeleteTask(3, time.Time{}); err != nil {
+				t.Fatalf("Failed to delete task: %v", err)
+			}
+			if _, err := store.GetTask(3); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected deleted task to be gone, got %v", err)
+			}
+			if next, _ := nextID(backend); next != 3 {
+				t.Errorf("Expected the next ID to be 3 after deleting the last task, got %d", next)
+			}
+
+			if err := backend.Save([]Task{{ID: 7, Name: "Only", Status: StatusOpen}}); err != nil {
+				t.Fatalf("Failed to save tasks: %v", err)
+			}
+			all, err := backend.Load()
+			if err != nil {
+				t.Fatalf("Failed to load tasks: %v", err)
+			}
+			if len(all) != 1 || all[0].ID != 7 {
+				t.Errorf("Expected save to replace all tasks, got %v", all)
+			}
+		})
+	}
+}
+
+func nextID(backend Backend) (int, error) {
+	var id int
+	err := backend.Mutate(func(tx Tx) error {
+		var err error
+		id, err = tx.NextID()
+		return err
+	})
+	return id, err
+}
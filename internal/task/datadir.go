@@ -0,0 +1,161 @@
+package task
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DataDirEnv names the environment variable that selects the data directory
+const DataDirEnv = "UNI_DIR"
+
+// DataDirName is the name of a data directory inside a repository
+const DataDirName = ".uni"
+
+// Sources of a data directory, see Location
+const (
+	// SourceEnv means the directory was given by UNI_DIR
+	SourceEnv = "env"
+	// SourceDirectory means a .uni directory was found in the start directory or above it
+	SourceDirectory = "directory"
+	// SourceWorktree means the .uni directory of the main checkout of a git worktree was used
+	SourceWorktree = "worktree"
+	// SourceGlobal means no .uni directory was found and ~/.uni is used
+	SourceGlobal = "global"
+)
+
+// Location is a data directory together with how it was found
+type Location struct {
+	// Dir is the absolute path of the data directory
+	Dir string
+	// Source is one of the Source constants
+	Source string
+	// Root is the git repository the search stopped at, if any
+	Root string
+}
+
+// DataDir returns the data directory for the current working directory, see LocateDataDir
+func DataDir() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	loc, err := LocateDataDir(wd)
+	if err != nil {
+		return "", err
+	}
+	return loc.Dir, nil
+}
+
+// LocateDataDir finds the data directory for start. UNI_DIR wins if set. Otherwise
+// start and its parents are searched for a .uni directory, stopping at the root of
+// the git repository start is in; .git may be a file, as in worktrees and
+// submodules. A worktree without a .uni of its own uses the one of its main
+// checkout. If nothing is found the data directory is ~/.uni.
+func LocateDataDir(start string) (Location, error) {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return Location{}, err
+		}
+		return Location{Dir: abs, Source: SourceEnv}, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return Location{}, err
+	}
+	global := Location{Dir: filepath.Join(homeDir, DataDirName), Source: SourceGlobal}
+
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return Location{}, err
+	}
+	for {
+		candidate := filepath.Join(dir, DataDirName)
+		if candidate == global.Dir {
+			return global, nil
+		}
+		if isDir(candidate) {
+			loc := Location{Dir: candidate, Source: SourceDirectory}
+			if exists(filepath.Join(dir, ".git")) {
+				loc.Root = dir
+			}
+			return loc, nil
+		}
+
+		if gitPath := filepath.Join(dir, ".git"); exists(gitPath) {
+			if main := mainCheckout(gitPath); main != "" && isDir(filepath.Join(main, DataDirName)) {
+				return Location{Dir: filepath.Join(main, DataDirName), Source: SourceWorktree, Root: dir}, nil
+			}
+			global.Root = dir
+			return global, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return global, nil
+		}
+		dir = parent
+	}
+}
+
+// mainCheckout returns the main working tree of the git worktree whose .git file
+// is at gitPath, or "" if gitPath is not the .git file of a worktree. Submodules
+// also have a .git file, but their git directory has no commondir.
+func mainCheckout(gitPath string) string {
+	gitDir := readGitDir(gitPath)
+	if gitDir == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return ""
+	}
+	common := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+	common = filepath.Clean(common)
+	if filepath.Base(common) != ".git" {
+		// A bare repository has no main working tree
+		return ""
+	}
+	return filepath.Dir(common)
+}
+
+// readGitDir returns the git directory a .git file points to, or "" if gitPath
+// is not such a file
+func readGitDir(gitPath string) string {
+	f, err := os.Open(gitPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(gitPath), gitDir)
+	}
+	return filepath.Clean(gitDir)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
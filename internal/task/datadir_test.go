@@ -0,0 +1,82 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mkdirs(t *testing.T, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocateDataDir(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	t.Setenv("HOME", home)
+	t.Setenv(DataDirEnv, "")
+	global := filepath.Join(home, ".uni")
+
+	repo := filepath.Join(root, "repo")
+	mkdirs(t, filepath.Join(repo, ".git"), filepath.Join(repo, ".uni"), filepath.Join(repo, "src", "pkg"))
+
+	// A submodule has a .git file pointing into the git directory of its superproject
+	sub := filepath.Join(repo, "vendor", "lib")
+	mkdirs(t, filepath.Join(sub, "src"), filepath.Join(repo, ".git", "modules", "lib"))
+	writeFile(t, filepath.Join(sub, ".git"), "gitdir: ../../.git/modules/lib\n")
+
+	// A worktree's git directory has a commondir leading back to the main repository
+	worktree := filepath.Join(root, "wt")
+	wtGitDir := filepath.Join(repo, ".git", "worktrees", "wt")
+	mkdirs(t, filepath.Join(worktree, "docs"), wtGitDir)
+	writeFile(t, filepath.Join(worktree, ".git"), "gitdir: "+wtGitDir+"\n")
+	writeFile(t, filepath.Join(wtGitDir, "commondir"), "../..\n")
+
+	plain := filepath.Join(root, "plain", "notes")
+	mkdirs(t, filepath.Join(root, "plain", ".uni"), plain, filepath.Join(root, "elsewhere"))
+
+	cases := []struct {
+		name  string
+		start string
+		want  Location
+	}{
+		{"repo root", repo, Location{Dir: filepath.Join(repo, ".uni"), Source: SourceDirectory, Root: repo}},
+		{"subdirectory", filepath.Join(repo, "src", "pkg"), Location{Dir: filepath.Join(repo, ".uni"), Source: SourceDirectory, Root: repo}},
+		{"submodule", filepath.Join(sub, "src"), Location{Dir: global, Source: SourceGlobal, Root: sub}},
+		{"worktree", filepath.Join(worktree, "docs"), Location{Dir: filepath.Join(repo, ".uni"), Source: SourceWorktree, Root: worktree}},
+		{"outside a repo", plain, Location{Dir: filepath.Join(root, "plain", ".uni"), Source: SourceDirectory}},
+		{"nothing found", filepath.Join(root, "elsewhere"), Location{Dir: global, Source: SourceGlobal}},
+	}
+	for _, c := range cases {
+		got, err := LocateDataDir(c.start)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.want, got)
+		}
+	}
+
+	// A .uni of its own takes precedence over the main checkout's
+	mkdirs(t, filepath.Join(worktree, ".uni"))
+	if got, _ := LocateDataDir(worktree); got.Dir != filepath.Join(worktree, ".uni") || got.Source != SourceDirectory {
+		t.Errorf("Expected the worktree's own .uni, got %+v", got)
+	}
+
+	t.Setenv(DataDirEnv, filepath.Join(root, "custom"))
+	if got, _ := LocateDataDir(repo); got.Dir != filepath.Join(root, "custom") || got.Source != SourceEnv {
+		t.Errorf("Expected UNI_DIR to win, got %+v", got)
+	}
+}
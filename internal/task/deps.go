@@ -0,0 +1,221 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrDependencyCycle is returned when linking tasks would make a task depend on itself
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// LinkTask records that task id is blocked by each of the blockers. If any
+// blocker is still open the task is moved to blocked.
+func (ts *TaskStore) LinkTask(id int, blockers []int) (*Task, error) {
+	var task *Task
+	err := ts.mutate(func(tx Tx) error {
+		var err error
+		task, err = tx.Get(id)
+		if err != nil {
+			return err
+		}
+
+		for _, blocker := range blockers {
+			if blocker == id {
+				return fmt.Errorf("task #%d cannot be blocked by itself: %w", id, ErrDependencyCycle)
+			}
+			if _, err := tx.Get(blocker); err != nil {
+				return err
+			}
+			if path := dependencyPath(tx, blocker, id); path != nil {
+				return fmt.Errorf("task #%d cannot be blocked by #%d, #%d already depends on it (%s): %w",
+					id, blocker, blocker, formatPath(path), ErrDependencyCycle)
+			}
+			if !containsID(task.BlockedBy, blocker) {
+				task.BlockedBy = append(task.BlockedBy, blocker)
+			}
+		}
+		sort.Ints(task.BlockedBy)
+
+		now := time.Now()
+		task.UpdatedAt = now
+		if err := refreshBlocked(tx, ts.workflow, task, now); err != nil {
+			return err
+		}
+		return tx.Put(task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// UnlinkTask removes blockers from task id. If no open blocker is left the task is unblocked.
+func (ts *TaskStore) UnlinkTask(id int, blockers []int) (*Task, error) {
+	var task *Task
+	err := ts.mutate(func(tx Tx) error {
+		var err error
+		task, err = tx.Get(id)
+		if err != nil {
+			return err
+		}
+
+		kept := []int{}
+		for _, blocker := range task.BlockedBy {
+			if !containsID(blockers, blocker) {
+				kept = append(kept, blocker)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		task.BlockedBy = kept
+
+		now := time.Now()
+		task.UpdatedAt = now
+		if err := refreshBlocked(tx, ts.workflow, task, now); err != nil {
+			return err
+		}
+		return tx.Put(task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// openBlockers returns the IDs of the tasks blocking t that are not closed yet in workflow w
+func openBlockers(r Reader, w *Workflow, t *Task) []int {
+	open := []int{}
+	for _, id := range t.BlockedBy {
+		blocker, err := r.Get(id)
+		if err != nil {
+			// A missing blocker no longer blocks anything
+			continue
+		}
+		if !w.IsClosed(blocker.Status) {
+			open = append(open, id)
+		}
+	}
+	return open
+}
+
+// refreshDependents re-evaluates every task blocked by the task with the given ID
+func refreshDependents(tx Tx, w *Workflow, id int, now time.Time) error {
+	dependents, err := tx.List(Filter{BlockedBy: id})
+	if err != nil {
+		return err
+	}
+
+	for i := range dependents {
+		dependent := &dependents[i]
+		before := dependent.Status
+		if err := refreshBlocked(tx, w, dependent, now); err != nil {
+			return err
+		}
+		if dependent.Status != before {
+			dependent.UpdatedAt = now
+			if err := tx.Put(dependent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refreshBlocked moves an active task to blocked while it has open blockers, and
+// back to the status it had before once the last blocker is closed. These automatic
+// moves are not subject to the transition rules of workflow w.
+func refreshBlocked(r Reader, w *Workflow, t *Task, now time.Time) error {
+	if _, ok := w.Status(StatusBlocked); !ok {
+		return nil
+	}
+
+	open := openBlockers(r, w, t)
+	switch {
+	case len(open) > 0 && w.IsActive(t.Status) && t.Status != StatusBlocked:
+		setStatus(t, StatusBlocked, "blocked by "+formatIDs(open), now)
+	case len(open) == 0 && len(t.BlockedBy) > 0 && t.Status == StatusBlocked:
+		setStatus(t, statusBeforeBlocked(w, t), "unblocked: all blockers closed", now)
+	}
+	return nil
+}
+
+// statusBeforeBlocked returns the active status the task had before it was last blocked
+func statusBeforeBlocked(w *Workflow, t *Task) TaskStatus {
+	for i := len(t.History) - 1; i >= 0; i-- {
+		tr := t.History[i]
+		if tr.To == StatusBlocked {
+			if tr.From != StatusBlocked && w.IsActive(tr.From) {
+				return tr.From
+			}
+			break
+		}
+	}
+	return w.Initial()
+}
+
+// setStatus changes the status of t and records the transition
+func setStatus(t *Task, status TaskStatus, reason string, now time.Time) {
+	t.History = append(t.History, Transition{From: t.Status, To: status, At: now, Reason: reason})
+	t.Status = status
+	t.UpdatedAt = now
+}
+
+// dependencyPath returns the chain of blockers leading from one task to another, or nil
+func dependencyPath(r Reader, from, to int) []int {
+	visited := map[int]bool{}
+	var walk func(id int) []int
+	walk = func(id int) []int {
+		if id == to {
+			return []int{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		t, err := r.Get(id)
+		if err != nil {
+			return nil
+		}
+		for _, next := range t.BlockedBy {
+			if path := walk(next); path != nil {
+				return append([]int{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+func containsID(ids []int, id int) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// formatIDs renders IDs as "#1, #2"
+func formatIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatPath renders a dependency chain as "#3 -> #7 -> #5"
+func formatPath(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, " -> ")
+}
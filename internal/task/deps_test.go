@@ -0,0 +1,125 @@
+package task
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTaskStore_LinkBlocksAndUnblocks(t *testing.T) {
+	for _, name := range []string{"memory", "sqlite"} {
+		t.Run(name, func(t *testing.T) {
+			backend, err := OpenBackend(name, t.TempDir())
+			if err != nil {
+				t.Fatalf("Failed to open backend: %v", err)
+			}
+			defer backend.Close()
+			store := NewTaskStoreWithBackend(backend)
+
+			for _, n := range []string{"Blocker A", "Blocker B", "Dependent"} {
+				if _, err := store.AddTask(n, ""); err != nil {
+					t.Fatalf("Failed to add task: %v", err)
+				}
+			}
+			if _, err := store.UpdateTaskStatus(3, StatusWorking); err != nil {
+				t.Fatalf("Failed to update task status: %v", err)
+			}
+
+			linked, err := store.LinkTask(3, []int{1, 2})
+			if err != nil {
+				t.Fatalf("Failed to link tasks: %v", err)
+			}
+			if linked.Status != StatusBlocked {
+				t.Errorf("Expected dependent to be blocked, got %s", linked.Status)
+			}
+
+			if _, err := store.UpdateTaskStatus(1, StatusDone); err != nil {
+				t.Fatalf("Failed to update task status: %v", err)
+			}
+			if dependent, _ := store.GetTask(3); dependent.Status != StatusBlocked {
+				t.Errorf("Expected dependent to stay blocked while #2 is open, got %s", dependent.Status)
+			}
+
+			if _, err := store.UpdateTaskStatus(2, StatusCancel); err != nil {
+				t.Fatalf("Failed to update task status: %v", err)
+			}
+			dependent, _ := store.GetTask(3)
+			if dependent.Status != StatusWorking {
+				t.Errorf("Expected dependent to go back to working, got %s", dependent.Status)
+			}
+
+			// Reopening a blocker blocks the dependent again
+			if _, err := store.UpdateTaskStatus(2, StatusOpen); err != nil {
+				t.Fatalf("Failed to update task status: %v", err)
+			}
+			if dependent, _ := store.GetTask(3); dependent.Status != StatusBlocked {
+				t.Errorf("Expected dependent to be blocked again, got %s", dependent.Status)
+			}
+
+			unlinked, err := store.UnlinkTask(3, []int{2})
+			if err != nil {
+				t.Fatalf("Failed to unlink tasks: %v", err)
+			}
+			if unlinked.Status != StatusWorking || len(unlinked.BlockedBy) != 1 {
+				t.Errorf("Expected unlinked task to be working and blocked by #1 only, got %s %v", unlinked.Status, unlinked.BlockedBy)
+			}
+		})
+	}
+}
+
+func TestTaskStore_ChangeStatusKeepsBlocked(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+	for _, n := range []string{"Blocker", "Dependent"} {
+		if _, err := store.AddTask(n, ""); err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+	}
+	if _, err := store.LinkTask(2, []int{1}); err != nil {
+		t.Fatalf("Failed to link tasks: %v", err)
+	}
+
+	moved, err := store.UpdateTaskStatus(2, StatusWorking)
+	if err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	if moved.Status != StatusBlocked {
+		t.Errorf("Expected the dependent to stay blocked while #1 is open, got %s", moved.Status)
+	}
+
+	// Once the blocker is closed it resumes the status it was moved to
+	if _, err := store.UpdateTaskStatus(1, StatusDone); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	if dependent, _ := store.GetTask(2); dependent.Status != StatusWorking {
+		t.Errorf("Expected the dependent to resume working, got %s", dependent.Status)
+	}
+}
+
+func TestTaskStore_LinkRefusesCycles(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+	for _, n := range []string{"One", "Two", "Three"} {
+		if _, err := store.AddTask(n, ""); err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+	}
+
+	if _, err := store.LinkTask(2, []int{1}); err != nil {
+		t.Fatalf("Failed to link tasks: %v", err)
+	}
+	if _, err := store.LinkTask(3, []int{2}); err != nil {
+		t.Fatalf("Failed to link tasks: %v", err)
+	}
+
+	if _, err := store.LinkTask(1, []int{3}); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected cycle to be refused, got %v", err)
+	}
+	if _, err := store.LinkTask(1, []int{1}); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected self link to be refused, got %v", err)
+	}
+	if _, err := store.LinkTask(1, []int{42}); err == nil {
+		t.Error("Expected link to missing task to be refused")
+	}
+
+	if task, _ := store.GetTask(1); len(task.BlockedBy) != 0 {
+		t.Errorf("Expected refused links to leave task untouched, got %v", task.BlockedBy)
+	}
+}
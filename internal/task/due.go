@@ -0,0 +1,77 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueDateLayout is the format due dates are shown and entered in
+const DueDateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseDue turns natural input into a due date relative to now. It accepts
+// today, tomorrow, weekday names (the next such day after today), offsets
+// like +3d or +2w, and ISO dates (2024-05-31). Due dates are whole days in
+// now's location.
+func ParseDue(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	today := startOfDay(now)
+
+	switch input {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if day, ok := weekdays[input]; ok {
+		days := (int(day)-int(today.Weekday())+6)%7 + 1
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if strings.HasPrefix(input, "+") && len(input) > 2 {
+		n, err := strconv.Atoi(input[1 : len(input)-1])
+		if err == nil && n >= 0 {
+			switch input[len(input)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
+	}
+
+	if due, err := time.ParseInLocation(DueDateLayout, input, now.Location()); err == nil {
+		return due, nil
+	}
+	if due, err := time.Parse(time.RFC3339, strings.ToUpper(input)); err == nil {
+		return startOfDay(due.In(now.Location())), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid due date %q (use today, tomorrow, a weekday, +3d, +2w or YYYY-MM-DD)", input)
+}
+
+// Overdue reports whether t is still open in the workflow and its due date has passed
+func (w *Workflow) Overdue(t Task, now time.Time) bool {
+	return t.DueAt != nil && !w.IsClosed(t.Status) && t.DueAt.Before(startOfDay(now))
+}
+
+// DueToday reports whether t is still open in the workflow and due on now's day
+func (w *Workflow) DueToday(t Task, now time.Time) bool {
+	return t.DueAt != nil && !w.IsClosed(t.Status) && startOfDay(*t.DueAt).Equal(startOfDay(now))
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2024, 5, 15, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"today", "2024-05-15"},
+		{"Tomorrow", "2024-05-16"},
+		{"fri", "2024-05-17"},
+		{"friday", "2024-05-17"},
+		{"wed", "2024-05-22"}, // the next Wednesday, not today
+		{"mon", "2024-05-20"},
+		{"+3d", "2024-05-18"},
+		{"+2w", "2024-05-29"},
+		{"+0d", "2024-05-15"},
+		{"2024-06-01", "2024-06-01"},
+		{"2024-06-01T10:00:00Z", "2024-06-01"},
+	}
+	for _, tt := range tests {
+		due, err := ParseDue(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseDue(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := due.Format(DueDateLayout); got != tt.want {
+			t.Errorf("ParseDue(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"", "someday", "+d", "+3m", "-3d", "2024-13-01"} {
+		if _, err := ParseDue(invalid, now); err == nil {
+			t.Errorf("Expected ParseDue(%q) to fail", invalid)
+		}
+	}
+}
+
+func TestWorkflow_Overdue(t *testing.T) {
+	now := time.Date(2024, 5, 15, 15, 30, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	today := startOfDay(now)
+	w := DefaultWorkflow()
+
+	overdue := Task{Status: StatusOpen, DueAt: &yesterday}
+	if !w.Overdue(overdue, now) || w.DueToday(overdue, now) {
+		t.Error("Expected task due yesterday to be overdue")
+	}
+
+	dueToday := Task{Status: StatusOpen, DueAt: &today}
+	if w.Overdue(dueToday, now) || !w.DueToday(dueToday, now) {
+		t.Error("Expected task due today to be due today and not overdue")
+	}
+
+	done := Task{Status: StatusDone, DueAt: &yesterday}
+	if w.Overdue(done, now) {
+		t.Error("Expected closed task not to be overdue")
+	}
+
+	if w.Overdue(Task{Status: StatusOpen}, now) {
+		t.Error("Expected task without due date not to be overdue")
+	}
+}
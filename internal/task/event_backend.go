@@ -0,0 +1,426 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of change recorded in the event log
+type EventType string
+
+const (
+	EventCreated       EventType = "created"
+	EventStatusChanged EventType = "status_changed"
+	EventEdited        EventType = "edited"
+	EventDeleted       EventType = "deleted"
+)
+
+// Event is a single change appended to events.jsonl
+type Event struct {
+	ID     string     `json:"id"`
+	Type   EventType  `json:"type"`
+	TaskID int        `json:"task_id"`
+	At     time.Time  `json:"at"`
+	From   TaskStatus `json:"from,omitempty"`
+	To     TaskStatus `json:"to,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+	Task   *Task      `json:"task,omitempty"`
+}
+
+// snapshot is the compacted state the event log is replayed on top of
+type snapshot struct {
+	LastEvent string    `json:"last_event,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Tasks     []Task    `json:"tasks"`
+}
+
+// Compactor is implemented by backends that can fold their history into a snapshot
+type Compactor interface {
+	// Compact folds all recorded changes into a snapshot and returns how many were folded
+	Compact() (int, error)
+}
+
+// eventBackend stores every change as one line in an append-only events.jsonl
+// and rebuilds the tasks by replaying it on top of snapshot.json. Appending
+// lines instead of rewriting a JSON array keeps git merges of a checked-in
+// store clean.
+type eventBackend struct {
+	dataDir string
+	mu      sync.Mutex
+}
+
+// NewEventBackend returns a backend that keeps an append-only event log in dataDir
+func NewEventBackend(dataDir string) Backend {
+	return &eventBackend{dataDir: dataDir}
+}
+
+func (b *eventBackend) eventsFile() string {
+	return filepath.Join(b.dataDir, "events.jsonl")
+}
+
+func (b *eventBackend) snapshotFile() string {
+	return filepath.Join(b.dataDir, "snapshot.json")
+}
+
+func (b *eventBackend) lockFile() string {
+	return filepath.Join(b.dataDir, "events.lock")
+}
+
+// readSnapshot returns the last snapshot, or an empty one if none was written yet
+func (b *eventBackend) readSnapshot() (*snapshot, error) {
+	snap := &snapshot{Tasks: []Task{}}
+
+	data, err := os.ReadFile(b.snapshotFile())
+	if os.IsNotExist(err) {
+		return snap, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", b.snapshotFile(), err)
+	}
+	return snap, nil
+}
+
+// readEvents returns the events recorded after the snapshot
+func (b *eventBackend) readEvents(snap *snapshot) ([]Event, error) {
+	f, err := os.Open(b.eventsFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %v", b.eventsFile(), line, err)
+		}
+		events = append(events, event)
+
+		// A crash between writing the snapshot and truncating the log leaves
+		// already-compacted events behind; drop everything up to the snapshot
+		if snap.LastEvent != "" && event.ID == snap.LastEvent {
+			events = events[:0]
+		}
+	}
+	return events, scanner.Err()
+}
+
+// replay rebuilds the tasks from the snapshot and the event log. It reports
+// whether tasks had to be renumbered, see renumbering.
+func (b *eventBackend) replay() ([]Task, []Event, bool, error) {
+	snap, err := b.readSnapshot()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	events, err := b.readEvents(snap)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	tx := &sliceTx{tasks: snap.Tasks}
+	ids := renumbering{}
+	for _, event := range events {
+		applyEvent(tx, ids.apply(tx, event))
+	}
+	return tx.tasks, events, len(ids) > 0, nil
+}
+
+// renumbering maps task IDs of the event log to the IDs they were replayed
+// under. Two branches that each created a task append created events with the
+// same ID, and a union merge of their logs keeps both; the later task gets the
+// next free ID and the events after it that use the old ID follow it, since a
+// merge puts one branch's events after the other's.
+type renumbering map[int]int
+
+// apply returns event with its task IDs renumbered, taking a new ID for a
+// task created under an ID that tx already has
+func (ids renumbering) apply(tx *sliceTx, event Event) Event {
+	if event.Type == EventCreated && event.Task != nil {
+		if _, err := tx.Get(event.TaskID); err == nil {
+			next, _ := tx.NextID()
+			for _, id := range ids {
+				if id >= next {
+					next = id + 1
+				}
+			}
+			ids[event.TaskID] = next
+		} else {
+			delete(ids, event.TaskID)
+		}
+	}
+	if len(ids) == 0 {
+		return event
+	}
+
+	event.TaskID = ids.id(event.TaskID)
+	if event.Task != nil {
+		task := event.Task.clone()
+		task.ID = ids.id(task.ID)
+		task.Parent = ids.id(task.Parent)
+		for i, blocker := range task.BlockedBy {
+			task.BlockedBy[i] = ids.id(blocker)
+		}
+		event.Task = &task
+	}
+	return event
+}
+
+func (ids renumbering) id(id int) int {
+	if renumbered, ok := ids[id]; ok {
+		return renumbered
+	}
+	return id
+}
+
+// applyEvent applies a single recorded change to the tasks in tx
+func applyEvent(tx *sliceTx, event Event) {
+	switch event.Type {
+	case EventCreated, EventEdited:
+		if event.Task != nil {
+			tx.Put(event.Task)
+		}
+	case EventStatusChanged:
+		task, err := tx.Get(event.TaskID)
+		if err != nil {
+			return
+		}
+		task.History = append(task.History, Transition{From: event.From, To: event.To, At: event.At, Reason: event.Reason})
+		task.Status = event.To
+		task.UpdatedAt = event.At
+		tx.Put(task)
+	case EventDeleted:
+		tx.Delete(event.TaskID)
+	}
+}
+
+// diffEvents returns the events that turn before into after
+func diffEvents(before, after []Task) []Event {
+	previous := make(map[int]Task, len(before))
+	for _, task := range before {
+		previous[task.ID] = task
+	}
+
+	events := []Event{}
+	current := make(map[int]bool, len(after))
+	for _, task := range after {
+		current[task.ID] = true
+	}
+	for _, task := range before {
+		if !current[task.ID] {
+			events = append(events, Event{Type: EventDeleted, TaskID: task.ID, At: time.Now()})
+		}
+	}
+	for _, task := range after {
+		task := task
+		old, ok := previous[task.ID]
+		switch {
+		case !ok:
+			events = append(events, Event{Type: EventCreated, TaskID: task.ID, At: task.CreatedAt, Task: &task})
+		case reflect.DeepEqual(old, task):
+			continue
+		case onlyStatusChanged(old, task):
+			last := task.History[len(task.History)-1]
+			events = append(events, Event{Type: EventStatusChanged, TaskID: task.ID, At: last.At, From: last.From, To: last.To, Reason: last.Reason})
+		default:
+			events = append(events, Event{Type: EventEdited, TaskID: task.ID, At: task.UpdatedAt, Task: &task})
+		}
+	}
+	return events
+}
+
+// onlyStatusChanged reports whether replaying a status_changed event is enough to turn old into task
+func onlyStatusChanged(old, task Task) bool {
+	if len(task.History) != len(old.History)+1 {
+		return false
+	}
+
+	last := task.History[len(task.History)-1]
+	if last.From != old.Status || last.To != task.Status || !last.At.Equal(task.UpdatedAt) {
+		return false
+	}
+
+	old = old.clone()
+	old.History = append(old.History, last)
+	old.Status = task.Status
+	old.UpdatedAt = task.UpdatedAt
+	return reflect.DeepEqual(old, task)
+}
+
+// appendEvents writes events to the end of the log and fsyncs it
+func (b *eventBackend) appendEvents(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = newEventID()
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(b.eventsFile(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeSnapshot atomically replaces the snapshot and then empties the log
+func (b *eventBackend) writeSnapshot(tasks []Task, lastEvent string) error {
+	data, err := json.MarshalIndent(snapshot{LastEvent: lastEvent, CreatedAt: time.Now(), Tasks: tasks}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(b.snapshotFile(), data, 0644); err != nil {
+		return err
+	}
+	return WriteFileAtomic(b.eventsFile(), nil, 0644)
+}
+
+func (b *eventBackend) Load() ([]Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tasks, _, _, err := b.replay()
+	return tasks, err
+}
+
+// Save replaces every task by writing a fresh snapshot and clearing the log
+func (b *eventBackend) Save(tasks []Task) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unlock, err := LockFile(b.lockFile())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return b.writeSnapshot(tasks, "")
+}
+
+func (b *eventBackend) Get(id int) (*Task, error) {
+	tasks, err := b.Load()
+	if err != nil {
+		return nil, err
+	}
+	return (&sliceTx{tasks: tasks}).Get(id)
+}
+
+func (b *eventBackend) List(filter Filter) ([]Task, error) {
+	tasks, err := b.Load()
+	if err != nil {
+		return nil, err
+	}
+	return (&sliceTx{tasks: tasks}).List(filter)
+}
+
+// Mutate replays the log, applies fn and appends the resulting changes as events
+func (b *eventBackend) Mutate(fn func(tx Tx) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unlock, err := LockFile(b.lockFile())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	before, events, renumbered, err := b.replay()
+	if err != nil {
+		return err
+	}
+	// Fix the IDs renumbered after a merge before recording events against them
+	if renumbered {
+		if err := b.writeSnapshot(before, events[len(events)-1].ID); err != nil {
+			return err
+		}
+	}
+
+	tx := &sliceTx{tasks: copyTasks(before)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return b.appendEvents(diffEvents(before, tx.tasks))
+}
+
+// Compact folds the event log into snapshot.json
+func (b *eventBackend) Compact() (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unlock, err := LockFile(b.lockFile())
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	tasks, events, _, err := b.replay()
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	if err := b.writeSnapshot(tasks, events[len(events)-1].ID); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+func (b *eventBackend) Close() error {
+	return nil
+}
+
+// newEventID returns a random identifier for an event
+func newEventID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
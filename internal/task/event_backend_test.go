@@ -0,0 +1,226 @@
+package task
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readEventLog(t *testing.T, dir string) []Event {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(dir, "events.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to open event log: %v", err)
+	}
+	defer f.Close()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Failed to parse event: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestEventBackend_RecordsChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	store := NewTaskStoreWithBackend(NewEventBackend(tempDir))
+
+	created, err := store.AddTask("Logged", "")
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if _, err := store.ChangeStatus(created.ID, StatusChange{Status: StatusWorking, Reason: "picked up"}); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+
+	edited, _ := store.GetTask(created.ID)
+	edited.Description = "Now with details"
+	if err := store.UpdateTask(edited); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	events := readEventLog(t, tempDir)
+	want := []EventType{EventCreated, EventStatusChanged, EventEdited}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(events))
+	}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, want[i], event.Type)
+		}
+	}
+	if events[1].From != StatusOpen || events[1].To != StatusWorking {
+		t.Errorf("Expected open -> working, got %s -> %s", events[1].From, events[1].To)
+	}
+
+	// A fresh backend rebuilds the same state by replaying the log
+	replayed, err := NewTaskStoreWithBackend(NewEventBackend(tempDir)).GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get replayed task: %v", err)
+	}
+	if replayed.Status != StatusWorking || replayed.Description != "Now with details" {
+		t.Errorf("Unexpected replayed task: %+v", replayed)
+	}
+	if len(replayed.History) != 1 || replayed.History[0].Reason != "picked up" {
+		t.Errorf("Expected replayed history with reason, got %+v", replayed.History)
+	}
+}
+
+func TestEventBackend_Compact(t *testing.T) {
+	tempDir := t.TempDir()
+	backend := NewEventBackend(tempDir)
+	store := NewTaskStoreWithBackend(backend)
+
+	for _, name := range []string{"One", "Two"} {
+		if _, err := store.AddTask(name, ""); err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+	}
+	if _, err := store.UpdateTaskStatus(1, StatusDone); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+
+	n, err := backend.(Compactor).Compact()
+	if err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 compacted events, got %d", n)
+	}
+	if events := readEventLog(t, tempDir); len(events) != 0 {
+		t.Errorf("Expected empty log after compaction, got %d events", len(events))
+	}
+
+	tasks, err := backend.Load()
+	if err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Status != StatusDone {
+		t.Errorf("Unexpected tasks after compaction: %+v", tasks)
+	}
+
+	// Changes after compaction are appended on top of the snapshot
+	if _, err := store.AddTask("Three", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if tasks, _ := backend.Load(); len(tasks) != 3 {
+		t.Errorf("Expected 3 tasks, got %d", len(tasks))
+	}
+}
+
+func TestEventBackend_SkipsCompactedEvents(t *testing.T) {
+	tempDir := t.TempDir()
+	backend := NewEventBackend(tempDir).(*eventBackend)
+	store := NewTaskStoreWithBackend(backend)
+
+	if _, err := store.AddTask("One", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	logged, err := os.ReadFile(backend.eventsFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := backend.Compact(); err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+
+	// Simulate a crash after the snapshot was written but before the log was emptied
+	if err := os.WriteFile(backend.eventsFile(), logged, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.UpdateTaskStatus(1, StatusWorking); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+
+	_, events, _, err := backend.replay()
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventStatusChanged {
+		t.Errorf("Expected only the status change to be replayed, got %+v", events)
+	}
+}
+
+func TestEventBackend_MergedLogsKeepBothTasks(t *testing.T) {
+	base := t.TempDir()
+	store := NewTaskStoreWithBackend(NewEventBackend(base))
+	if _, err := store.AddTask("Shared", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	shared, err := os.ReadFile(filepath.Join(base, "events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two branches each add tasks on top of the shared log
+	branch := func(names ...string) []byte {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "events.jsonl"), shared, 0644); err != nil {
+			t.Fatal(err)
+		}
+		store := NewTaskStoreWithBackend(NewEventBackend(dir))
+		parent, _ := store.AddTask(names[0], "")
+		for _, name := range names[1:] {
+			if _, err := store.CreateTask(TaskSpec{Name: name, Parent: parent.ID}); err != nil {
+				t.Fatalf("Failed to add task: %v", err)
+			}
+		}
+		if _, err := store.UpdateTaskStatus(parent.ID, StatusWorking); err != nil {
+			t.Fatalf("Failed to update task status: %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "events.jsonl"))
+		return data[len(shared):]
+	}
+	onMain := branch("From main")
+	feature := branch("From feature", "Feature subtask")
+
+	// A union merge keeps the shared lines once and both branches' new lines
+	merged := t.TempDir()
+	log := append(append(append([]byte(nil), shared...), onMain...), feature...)
+	if err := os.WriteFile(filepath.Join(merged, "events.jsonl"), log, 0644); err != nil {
+		t.Fatal(err)
+	}
+	store = NewTaskStoreWithBackend(NewEventBackend(merged))
+
+	tasks := store.ListTasks()
+	want := []struct {
+		name   string
+		parent int
+		status TaskStatus
+	}{
+		{"Shared", 0, StatusOpen},
+		{"From main", 0, StatusWorking},
+		{"From feature", 0, StatusWorking},
+		{"Feature subtask", 3, StatusOpen},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("Expected %d tasks after the merge, got %+v", len(want), tasks)
+	}
+	for i, w := range want {
+		got := tasks[i]
+		if got.ID != i+1 || got.Name != w.name || got.Parent != w.parent || got.Status != w.status {
+			t.Errorf("Expected #%d %q (parent %d, %s), got #%d %q (parent %d, %s)",
+				i+1, w.name, w.parent, w.status, got.ID, got.Name, got.Parent, got.Status)
+		}
+	}
+
+	// The next change fixes the new IDs, so it applies to the task it names
+	if _, err := store.UpdateTaskStatus(2, StatusDone); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	if task, _ := store.GetTask(2); task.Name != "From main" || task.Status != StatusDone {
+		t.Errorf("Expected From main to be done, got %q %s", task.Name, task.Status)
+	}
+	if task, _ := store.GetTask(3); task.Name != "From feature" || task.Status != StatusWorking {
+		t.Errorf("Expected From feature to be untouched, got %q %s", task.Name, task.Status)
+	}
+}
@@ -0,0 +1,163 @@
+package task
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// jsonBackend stores all tasks in a single tasks.json file.
+// Writes go through a temp file and rename, and every load-modify-save
+// cycle holds an advisory lock on tasks.lock so concurrent processes
+// don't lose each other's updates.
+type jsonBackend struct {
+	dataDir string
+
+	mu      sync.Mutex
+	loaded  bool
+	version string // hash of tasks.json as of the last load or save
+}
+
+// NewJSONBackend returns a backend that stores tasks in dataDir/tasks.json
+func NewJSONBackend(dataDir string) Backend {
+	return &jsonBackend{dataDir: dataDir}
+}
+
+// tasksFile returns the path to the tasks.json file
+func (b *jsonBackend) tasksFile() string {
+	return filepath.Join(b.dataDir, "tasks.json")
+}
+
+// lockFile returns the path to the lock file guarding tasks.json
+func (b *jsonBackend) lockFile() string {
+	return filepath.Join(b.dataDir, "tasks.lock")
+}
+
+// readFile returns the raw contents of tasks.json and its version
+func (b *jsonBackend) readFile() ([]byte, string, error) {
+	data, err := os.ReadFile(b.tasksFile())
+	if os.IsNotExist(err) {
+		// File doesn't exist, start with empty slice
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+// Load loads tasks from the JSON file
+func (b *jsonBackend) Load() ([]Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.load()
+}
+
+func (b *jsonBackend) load() ([]Task, error) {
+	data, version, err := b.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []Task{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", b.tasksFile(), err)
+		}
+	}
+
+	b.loaded = true
+	b.version = version
+	return tasks, nil
+}
+
+// Save saves tasks to the JSON file. If the file changed on disk since this
+// backend last read it, Save fails with ErrConflict instead of overwriting it.
+func (b *jsonBackend) Save(tasks []Task) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unlock, err := LockFile(b.lockFile())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if b.loaded {
+		_, current, err := b.readFile()
+		if err != nil {
+			return err
+		}
+		if current != b.version {
+			return fmt.Errorf("%s was modified by another process since it was read: %w", b.tasksFile(), ErrConflict)
+		}
+	}
+
+	return b.save(tasks)
+}
+
+func (b *jsonBackend) save(tasks []Task) error {
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(b.tasksFile(), data, 0644); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	b.loaded = true
+	b.version = hex.EncodeToString(sum[:])
+	return nil
+}
+
+func (b *jsonBackend) Get(id int) (*Task, error) {
+	tasks, err := b.Load()
+	if err != nil {
+		return nil, err
+	}
+	return (&sliceTx{tasks: tasks}).Get(id)
+}
+
+func (b *jsonBackend) List(filter Filter) ([]Task, error) {
+	tasks, err := b.Load()
+	if err != nil {
+		return nil, err
+	}
+	return (&sliceTx{tasks: tasks}).List(filter)
+}
+
+// Mutate reloads tasks.json and applies fn while holding the lock, so the
+// whole load-modify-save cycle is atomic with respect to other processes
+func (b *jsonBackend) Mutate(fn func(tx Tx) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unlock, err := LockFile(b.lockFile())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tasks, err := b.load()
+	if err != nil {
+		return err
+	}
+
+	tx := &sliceTx{tasks: tasks}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return b.save(tx.tasks)
+}
+
+func (b *jsonBackend) Close() error {
+	return nil
+}
@@ -0,0 +1,127 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestJSONBackend_ConcurrentAdds(t *testing.T) {
+	tempDir := t.TempDir()
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each store has its own backend, like separate uni processes
+			store := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
+			if _, err := store.AddTask("Parallel", ""); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	tasks, err := NewJSONBackend(tempDir).Load()
+	if err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	if len(tasks) != workers {
+		t.Fatalf("Expected %d tasks, got %d", workers, len(tasks))
+	}
+
+	seen := map[int]bool{}
+	for _, task := range tasks {
+		if seen[task.ID] {
+			t.Errorf("Duplicate task ID %d", task.ID)
+		}
+		seen[task.ID] = true
+	}
+}
+
+func TestJSONBackend_SaveConflict(t *testing.T) {
+	tempDir := t.TempDir()
+
+	stale := NewJSONBackend(tempDir)
+	if _, err := stale.Load(); err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+
+	other := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
+	if _, err := other.AddTask("Newer", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	err := stale.Save([]Task{})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected ErrConflict when saving a stale snapshot, got %v", err)
+	}
+
+	tasks, err := NewJSONBackend(tempDir).Load()
+	if err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("Expected newer task to survive, got %d tasks", len(tasks))
+	}
+}
+
+func TestJSONBackend_AtomicWriteLeavesNoTempFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	store := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
+	if _, err := store.AddTask("Task", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(tempDir, ".tasks.json.tmp-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected no leftover temp files, got %v", matches)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, "tasks.json")); err != nil {
+		t.Errorf("Expected tasks.json to exist: %v", err)
+	}
+}
+
+func TestTaskStore_UpdateTaskConflict(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+
+	created, err := store.AddTask("Original", "")
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	stale, err := store.GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+
+	if _, err := store.UpdateTaskStatus(created.ID, StatusWorking); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+
+	stale.Name = "Edited"
+	if err := store.UpdateTask(stale); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected ErrConflict when updating a stale task, got %v", err)
+	}
+
+	fresh, _ := store.GetTask(created.ID)
+	fresh.Name = "Edited"
+	if err := store.UpdateTask(fresh); err != nil {
+		t.Fatalf("Failed to update fresh task: %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package task
+
+// LockFile is a no-op on platforms without flock
+func LockFile(path string) (func() error, error) {
+	return func() error { return nil }, nil
+}
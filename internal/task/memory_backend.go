@@ -0,0 +1,62 @@
+package task
+
+import "sync"
+
+// memoryBackend keeps tasks in memory only; useful for tests and dry runs
+type memoryBackend struct {
+	mu    sync.Mutex
+	tasks []Task
+}
+
+// NewMemoryBackend returns an empty backend that is never persisted
+func NewMemoryBackend() Backend {
+	return &memoryBackend{tasks: []Task{}}
+}
+
+func (b *memoryBackend) Load() ([]Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return copyTasks(b.tasks), nil
+}
+
+func (b *memoryBackend) Save(tasks []Task) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tasks = copyTasks(tasks)
+	return nil
+}
+
+func (b *memoryBackend) Get(id int) (*Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return (&sliceTx{tasks: b.tasks}).Get(id)
+}
+
+func (b *memoryBackend) List(filter Filter) ([]Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return (&sliceTx{tasks: b.tasks}).List(filter)
+}
+
+func (b *memoryBackend) Mutate(fn func(tx Tx) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tx := &sliceTx{tasks: copyTasks(b.tasks)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	b.tasks = tx.tasks
+	return nil
+}
+
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+// copyTasks returns a copy of tasks that does not share the backing array
+func copyTasks(tasks []Task) []Task {
+	out := make([]Task, len(tasks))
+	copy(out, tasks)
+	return out
+}
@@ -0,0 +1,115 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Priority ranks how urgent a task is, from P0 (most urgent) to P3
+type Priority string
+
+const (
+	PriorityNone Priority = ""
+	PriorityP0   Priority = "P0"
+	PriorityP1   Priority = "P1"
+	PriorityP2   Priority = "P2"
+	PriorityP3   Priority = "P3"
+)
+
+// Priorities lists the valid priorities, most urgent first
+var Priorities = []Priority{PriorityP0, PriorityP1, PriorityP2, PriorityP3}
+
+// ParsePriority accepts P0-P3 in any case, or just the digit; an empty string or "none" clears the priority
+func ParsePriority(input string) (Priority, error) {
+	input = strings.ToUpper(strings.TrimSpace(input))
+	if input == "" || input == "NONE" {
+		return PriorityNone, nil
+	}
+	if !strings.HasPrefix(input, "P") {
+		input = "P" + input
+	}
+	for _, p := range Priorities {
+		if Priority(input) == p {
+			return p, nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q. Valid priorities: %v", input, Priorities)
+}
+
+// rank orders priorities for sorting; tasks without a priority come last
+func (p Priority) rank() int {
+	for i, candidate := range Priorities {
+		if p == candidate {
+			return i
+		}
+	}
+	return len(Priorities)
+}
+
+// Sort keys accepted by SortTasks
+const (
+	SortByID       = "id"
+	SortByPriority = "priority"
+	SortByDue      = "due"
+)
+
+// SortKeys lists the keys tasks can be sorted by
+var SortKeys = []string{SortByID, SortByPriority, SortByDue}
+
+// SortTasks sorts tasks in place. "priority" sorts by priority, then due date, then ID;
+// "due" by due date, then priority, then ID. Tasks without a priority or due date come last.
+func SortTasks(tasks []Task, key string) error {
+	var less func(a, b Task) bool
+	switch key {
+	case SortByID, "":
+		less = func(a, b Task) bool { return a.ID < b.ID }
+	case SortByPriority:
+		less = func(a, b Task) bool {
+			if c := comparePriority(a, b); c != 0 {
+				return c < 0
+			}
+			if c := compareDue(a, b); c != 0 {
+				return c < 0
+			}
+			return a.ID < b.ID
+		}
+	case SortByDue:
+		less = func(a, b Task) bool {
+			if c := compareDue(a, b); c != 0 {
+				return c < 0
+			}
+			if c := comparePriority(a, b); c != 0 {
+				return c < 0
+			}
+			return a.ID < b.ID
+		}
+	default:
+		return fmt.Errorf("invalid sort key %q. Valid keys: %v", key, SortKeys)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return less(tasks[i], tasks[j])
+	})
+	return nil
+}
+
+func comparePriority(a, b Task) int {
+	return a.Priority.rank() - b.Priority.rank()
+}
+
+func compareDue(a, b Task) int {
+	switch {
+	case a.DueAt == nil && b.DueAt == nil:
+		return 0
+	case a.DueAt == nil:
+		return 1
+	case b.DueAt == nil:
+		return -1
+	case a.DueAt.Before(*b.DueAt):
+		return -1
+	case b.DueAt.Before(*a.DueAt):
+		return 1
+	}
+	return 0
+}
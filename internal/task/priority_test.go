@@ -0,0 +1,95 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePriority(t *testing.T) {
+	for input, want := range map[string]Priority{"P0": PriorityP0, "p1": PriorityP1, "2": PriorityP2, " P3 ": PriorityP3, "": PriorityNone, "none": PriorityNone} {
+		got, err := ParsePriority(input)
+		if err != nil {
+			t.Errorf("ParsePriority(%q) failed: %v", input, err)
+		}
+		if got != want {
+			t.Errorf("ParsePriority(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for _, invalid := range []string{"P4", "high", "-1"} {
+		if _, err := ParsePriority(invalid); err == nil {
+			t.Errorf("Expected ParsePriority(%q) to fail", invalid)
+		}
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	day := func(d int) *time.Time {
+		due := time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+		return &due
+	}
+	tasks := []Task{
+		{ID: 1},
+		{ID: 2, Priority: PriorityP2, DueAt: day(10)},
+		{ID: 3, Priority: PriorityP0},
+		{ID: 4, Priority: PriorityP0, DueAt: day(20)},
+		{ID: 5, DueAt: day(1)},
+		{ID: 6, Priority: PriorityP2, DueAt: day(10)},
+	}
+
+	ids := func(tasks []Task) []int {
+		out := make([]int, len(tasks))
+		for i, task := range tasks {
+			out[i] = task.ID
+		}
+		return out
+	}
+	check := func(key string, want []int) {
+		sorted := append([]Task(nil), tasks...)
+		if err := SortTasks(sorted, key); err != nil {
+			t.Fatalf("SortTasks(%s) failed: %v", key, err)
+		}
+		got := ids(sorted)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("SortTasks(%s) = %v, want %v", key, got, want)
+				return
+			}
+		}
+	}
+
+	check(SortByPriority, []int{4, 3, 2, 6, 5, 1})
+	check(SortByDue, []int{5, 2, 6, 4, 3, 1})
+	check(SortByID, []int{1, 2, 3, 4, 5, 6})
+
+	if err := SortTasks(tasks, "name"); err == nil {
+		t.Error("Expected unknown sort key to fail")
+	}
+}
+
+func TestTaskStore_PriorityAndDue(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+	due := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	task, err := store.CreateTask(TaskSpec{Name: "Ship", Priority: "p1", DueAt: &due})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if task.Priority != PriorityP1 || task.DueAt == nil || !task.DueAt.Equal(due) {
+		t.Errorf("Expected P1 due %v, got %q due %v", due, task.Priority, task.DueAt)
+	}
+
+	if _, err := store.CreateTask(TaskSpec{Name: "Bad", Priority: "P7"}); err == nil {
+		t.Error("Expected invalid priority to be rejected")
+	}
+
+	task.Priority = PriorityP0
+	task.DueAt = nil
+	if err := store.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	updated, _ := store.GetTask(task.ID)
+	if updated.Priority != PriorityP0 || updated.DueAt != nil {
+		t.Errorf("Expected P0 without due date, got %q due %v", updated.Priority, updated.DueAt)
+	}
+}
@@ -0,0 +1,81 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// DefaultProject names the project whose tasks live in the data directory itself
+const DefaultProject = "default"
+
+// ProjectsDirName is the directory inside a data directory that holds the other projects
+const ProjectsDirName = "projects"
+
+// projectName matches valid project names, e.g. infra or web-2
+var projectName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateProjectName rejects names that cannot be used for a new project
+func ValidateProjectName(name string) error {
+	if name == DefaultProject {
+		return fmt.Errorf("project name %q is reserved", name)
+	}
+	if !projectName.MatchString(name) {
+		return fmt.Errorf("invalid project name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// ProjectDir returns the directory holding the tasks of a project. The default
+// project lives in dataDir itself, every other one in dataDir/projects/<name>.
+func ProjectDir(dataDir, name string) string {
+	if name == "" || name == DefaultProject {
+		return dataDir
+	}
+	return filepath.Join(dataDir, ProjectsDirName, name)
+}
+
+// Projects returns the names of the projects in dataDir: the default project
+// first, then the others in alphabetical order
+func Projects(dataDir string) ([]string, error) {
+	names := []string{DefaultProject}
+
+	entries, err := os.ReadDir(filepath.Join(dataDir, ProjectsDirName))
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var others []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProjectName(entry.Name()) == nil {
+			others = append(others, entry.Name())
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...), nil
+}
+
+// HasProject reports whether dataDir has a project with the given name
+func HasProject(dataDir, name string) bool {
+	if name == DefaultProject {
+		return true
+	}
+	return ValidateProjectName(name) == nil && isDir(ProjectDir(dataDir, name))
+}
+
+// AddProject creates a project in dataDir
+func AddProject(dataDir, name string) error {
+	if err := ValidateProjectName(name); err != nil {
+		return err
+	}
+	dir := ProjectDir(dataDir, name)
+	if exists(dir) {
+		return fmt.Errorf("project %s already exists", name)
+	}
+	return os.MkdirAll(dir, 0755)
+}
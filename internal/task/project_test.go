@@ -0,0 +1,48 @@
+package task
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProjects(t *testing.T) {
+	dir := t.TempDir()
+	if names, err := Projects(dir); err != nil || !reflect.DeepEqual(names, []string{DefaultProject}) {
+		t.Fatalf("Expected only the default project, got %v (%v)", names, err)
+	}
+
+	for _, name := range []string{"web", "infra"} {
+		if err := AddProject(dir, name); err != nil {
+			t.Fatalf("Failed to add project %s: %v", name, err)
+		}
+	}
+	if err := AddProject(dir, "web"); err == nil {
+		t.Error("Expected adding an existing project to fail")
+	}
+	mkdirs(t, filepath.Join(dir, ProjectsDirName, "Not A Project"))
+
+	names, err := Projects(dir)
+	if err != nil || !reflect.DeepEqual(names, []string{DefaultProject, "infra", "web"}) {
+		t.Errorf("Expected [default infra web], got %v (%v)", names, err)
+	}
+	if !HasProject(dir, "infra") || HasProject(dir, "ops") || !HasProject(dir, DefaultProject) {
+		t.Error("Expected HasProject to report existing projects only")
+	}
+	if got := ProjectDir(dir, DefaultProject); got != dir {
+		t.Errorf("Expected the default project in the data directory, got %s", got)
+	}
+}
+
+func TestValidateProjectName(t *testing.T) {
+	for _, name := range []string{"infra", "web-2", "a_b"} {
+		if err := ValidateProjectName(name); err != nil {
+			t.Errorf("Expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", DefaultProject, "Infra", "-x", "a/b", "a b", ".."} {
+		if err := ValidateProjectName(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package task
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema keeps the indexed fields in columns and the full task as JSON in data,
+// so new task fields don't need a schema migration
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	id         INTEGER PRIMARY KEY,
+	status     TEXT    NOT NULL,
+	updated_at INTEGER NOT NULL,
+	data       TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS tasks_status ON tasks(status);
+CREATE INDEX IF NOT EXISTS tasks_updated_at ON tasks(updated_at);
+CREATE INDEX IF NOT EXISTS tasks_parent ON tasks(json_extract(data, '$.parent'));
+CREATE TABLE IF NOT EXISTS task_blockers (
+	task_id    INTEGER NOT NULL,
+	blocker_id INTEGER NOT NULL,
+	PRIMARY KEY (task_id, blocker_id)
+);
+CREATE INDEX IF NOT EXISTS task_blockers_blocker ON task_blockers(blocker_id);
+`
+
+// querier is the subset of *sql.DB and *sql.Tx used by the sqlite backend
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// sqliteBackend stores tasks in an embedded SQLite database at dataDir/tasks.db
+type sqliteBackend struct {
+	db *sql.DB
+}
+
+// NewSQLiteBackend opens (creating if needed) the SQLite database in dataDir
+func NewSQLiteBackend(dataDir string) (Backend, error) {
+	dsn := "file:" + filepath.Join(dataDir, "tasks.db") +
+		"?_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize sqlite store: %v", err)
+	}
+
+	return &sqliteBackend{db: db}, nil
+}
+
+func (b *sqliteBackend) Load() ([]Task, error) {
+	return sqliteList(b.db, Filter{})
+}
+
+func (b *sqliteBackend) Save(tasks []Task) error {
+	return b.inTx(func(q querier) error {
+		if _, err := q.Exec(`DELETE FROM tasks`); err != nil {
+			return err
+		}
+		if _, err := q.Exec(`DELETE FROM task_blockers`); err != nil {
+			return err
+		}
+		for i := range tasks {
+			if err := sqlitePut(q, &tasks[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *sqliteBackend) Get(id int) (*Task, error) {
+	return sqliteGet(b.db, id)
+}
+
+func (b *sqliteBackend) List(filter Filter) ([]Task, error) {
+	return sqliteList(b.db, filter)
+}
+
+func (b *sqliteBackend) Mutate(fn func(tx Tx) error) error {
+	return b.inTx(func(q querier) error {
+		return fn(&sqliteTx{q: q})
+	})
+}
+
+func (b *sqliteBackend) Close() error {
+	return b.db.Close()
+}
+
+// inTx runs fn in a database transaction, committing only if fn succeeds
+func (b *sqliteBackend) inTx(fn func(q querier) error) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqliteTx implements Tx inside a database transaction
+type sqliteTx struct {
+	q querier
+}
+
+func (tx *sqliteTx) Get(id int) (*Task, error) {
+	return sqliteGet(tx.q, id)
+}
+
+func (tx *sqliteTx) List(filter Filter) ([]Task, error) {
+	return sqliteList(tx.q, filter)
+}
+
+func (tx *sqliteTx) Put(task *Task) error {
+	return sqlitePut(tx.q, task)
+}
+
+func (tx *sqliteTx) Delete(id int) error {
+	res, err := tx.q.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &NotFoundError{ID: id}
+	}
+	_, err = tx.q.Exec(`DELETE FROM task_blockers WHERE task_id = ?`, id)
+	return err
+}
+
+func (tx *sqliteTx) NextID() (int, error) {
+	var id int
+	err := tx.q.QueryRow(`SELECT COALESCE(MAX(id), 0) + 1 FROM tasks`).Scan(&id)
+	return id, err
+}
+
+func sqliteGet(q querier, id int) (*Task, error) {
+	var data string
+	err := q.QueryRow(`SELECT data FROM tasks WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, &NotFoundError{ID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// sqliteList pushes the status, parent and blocker parts of the filter down to their
+// indexes and applies the rest of the filter in Go
+func sqliteList(q querier, filter Filter) ([]Task, error) {
+	where := []string{}
+	args := []interface{}{}
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			placeholders[i] = "?"
+			args = append(args, string(status))
+		}
+		where = append(where, `status IN (`+strings.Join(placeholders, ", ")+`)`)
+	}
+	if filter.Parent != 0 {
+		where = append(where, `json_extract(data, '$.parent') = ?`)
+		args = append(args, filter.Parent)
+	}
+	if filter.BlockedBy != 0 {
+		where = append(where, `id IN (SELECT task_id FROM task_blockers WHERE blocker_id = ?)`)
+		args = append(args, filter.BlockedBy)
+	}
+
+	query := `SELECT data FROM tasks`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY id`
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var task Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return nil, err
+		}
+		if filter.Matches(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, rows.Err()
+}
+
+func sqlitePut(q querier, task *Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(
+		`INSERT INTO tasks (id, status, updated_at, data) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET status = excluded.status, updated_at = excluded.updated_at, data = excluded.data`,
+		task.ID, string(task.Status), task.UpdatedAt.UnixNano(), string(data),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(`DELETE FROM task_blockers WHERE task_id = ?`, task.ID); err != nil {
+		return err
+	}
+	for _, blocker := range task.BlockedBy {
+		if _, err := q.Exec(`INSERT INTO task_blockers (task_id, blocker_id) VALUES (?, ?)`, task.ID, blocker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
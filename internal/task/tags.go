@@ -0,0 +1,87 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HasTag reports whether the task carries the tag
+func (t Task) HasTag(tag string) bool {
+	tag = strings.ToLower(tag)
+	for _, candidate := range t.Tags {
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TagTask adds and removes tags on a task
+func (ts *TaskStore) TagTask(id int, add, remove []string) (*Task, error) {
+	add, err := NormalizeTags(add)
+	if err != nil {
+		return nil, err
+	}
+	remove, err = NormalizeTags(remove)
+	if err != nil {
+		return nil, err
+	}
+
+	var task *Task
+	err = ts.mutate(func(tx Tx) error {
+		var err error
+		task, err = tx.Get(id)
+		if err != nil {
+			return err
+		}
+
+		tags := []string{}
+		for _, tag := range task.Tags {
+			if !containsTag(remove, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		tags, _ = NormalizeTags(append(tags, add...))
+
+		task.Tags = tags
+		task.UpdatedAt = time.Now()
+		return tx.Put(task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// NormalizeTags lowercases, de-duplicates and sorts tags, rejecting
+// empty tags and tags containing whitespace or commas
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	out := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || strings.ContainsAny(tag, " \t\n,") {
+			return nil, fmt.Errorf("invalid tag %q: tags must be non-empty and contain no spaces or commas", tag)
+		}
+		if !containsTag(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, candidate := range tags {
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
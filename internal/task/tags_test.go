@@ -0,0 +1,51 @@
+package task
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaskStore_Tags(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+
+	task, err := store.CreateTask(TaskSpec{Name: "Tagged", Tags: []string{"Backend", "urgent", "backend"}})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if want := []string{"backend", "urgent"}; !reflect.DeepEqual(task.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, task.Tags)
+	}
+	if _, err := store.CreateTask(TaskSpec{Name: "Other", Tags: []string{"backend"}}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	task, err = store.TagTask(1, []string{"API"}, []string{"urgent", "missing"})
+	if err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+	if want := []string{"api", "backend"}; !reflect.DeepEqual(task.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, task.Tags)
+	}
+	if !task.HasTag("API") {
+		t.Error("Expected HasTag to ignore case")
+	}
+
+	for _, invalid := range []string{"", "two words", "a,b"} {
+		if _, err := store.TagTask(1, []string{invalid}, nil); err == nil {
+			t.Errorf("Expected tag %q to be rejected", invalid)
+		}
+	}
+
+	// Every tag of the filter must be present
+	tasks, err := store.FindTasks(Filter{Tags: []string{"backend"}})
+	if err != nil {
+		t.Fatalf("Failed to find tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("Expected 2 tasks tagged backend, got %d", len(tasks))
+	}
+	tasks, _ = store.FindTasks(Filter{Tags: []string{"backend", "api"}})
+	if len(tasks) != 1 || tasks[0].ID != 1 {
+		t.Errorf("Expected only task 1 tagged backend and api, got %v", tasks)
+	}
+}
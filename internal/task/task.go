@@ -1,10 +1,9 @@
 package task
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"time"
 )
@@ -20,132 +19,257 @@ const (
 	StatusCancel  TaskStatus = "cancel"
 )
 
+// ErrConflict is returned when a write would overwrite changes made since the data was read
+var ErrConflict = errors.New("conflicting update")
+
+// ErrNotFound matches the error returned for a task ID that does not exist
+var ErrNotFound = errors.New("task not found")
+
+// ErrInUse is returned when deleting a task that other tasks still refer to
+var ErrInUse = errors.New("task is referenced by other tasks")
+
+// NotFoundError is returned when no task has the requested ID
+type NotFoundError struct {
+	ID int
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("task with ID %d not found", e.ID)
+}
+
+// Is makes errors.Is(err, ErrNotFound) match
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // Task represents a single task
 type Task struct {
-	ID          int        `json:"id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	History     []Transition `json:"history,omitempty" yaml:"history,omitempty"`
+	BlockedBy   []int        `json:"blocked_by,omitempty" yaml:"blocked_by,omitempty"`
+	Parent      int          `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Tags        []string     `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Priority    Priority     `json:"priority,omitempty" yaml:"priority,omitempty"`
+	DueAt       *time.Time   `json:"due_at,omitempty" yaml:"due_at,omitempty"`
+	// Project is set when the task is read through a client of a named project;
+	// it is not stored, as a project's tasks live in a directory of their own
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	// Store is set when the task is read through a client opened by registered
+	// store name; like Project it is not stored
+	Store string `json:"store,omitempty" yaml:"store,omitempty"`
+}
+
+// TaskSpec describes a task to create
+type TaskSpec struct {
+	Name        string
+	Description string
+	// Parent is the ID of the task this one is a subtask of (0 for none)
+	Parent   int
+	Tags     []string
+	Priority Priority
+	DueAt    *time.Time
+}
+
+// Transition records a single status change of a task
+type Transition struct {
+	From   TaskStatus `json:"from"`
+	To     TaskStatus `json:"to"`
+	At     time.Time  `json:"at"`
+	Reason string     `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// StatusChange describes a requested status update
+type StatusChange struct {
+	Status TaskStatus
+	// Reason is an optional note stored with the transition
+	Reason string
+	// Cascade applies a closing status to all open subtasks as well
+	Cascade bool
+	// UpdatedAt, if set, makes the change fail with ErrConflict unless the task
+	// still carries this UpdatedAt
+	UpdatedAt time.Time
+}
+
+// clone returns a copy of the task that shares no slices with t
+func (t Task) clone() Task {
+	if t.History != nil {
+		t.History = append([]Transition(nil), t.History...)
+	}
+	if t.BlockedBy != nil {
+		t.BlockedBy = append([]int(nil), t.BlockedBy...)
+	}
+	if t.Tags != nil {
+		t.Tags = append([]string(nil), t.Tags...)
+	}
+	if t.DueAt != nil {
+		due := *t.DueAt
+		t.DueAt = &due
+	}
+	return t
 }
 
 // TaskStore manages tasks
 type TaskStore struct {
-	dataDir string
-	tasks   []Task
+	backend  Backend
+	hooks    []ChangeHook
+	workflow *Workflow
 }
 
-// NewTaskStore creates a new task store
+// ChangeHook is called after a store mutation commits, with the tasks it wrote
+type ChangeHook func(changed []Task)
+
+// NewTaskStore creates a new task store using the default backend
 func NewTaskStore() (*TaskStore, error) {
-	dataDir, err := getDataDir()
+	dataDir, err := DataDir()
 	if err != nil {
 		return nil, err
 	}
 
-	store := &TaskStore{
-		dataDir: dataDir,
-		tasks:   []Task{},
-	}
+	return OpenTaskStore(dataDir, DefaultBackend)
+}
 
-	if err := store.ensureDataDir(); err != nil {
+// OpenTaskStore creates a task store in dataDir using the named backend
+func OpenTaskStore(dataDir, backendName string) (*TaskStore, error) {
+	if err := ensureDataDir(dataDir); err != nil {
 		return nil, err
 	}
 
-	if err := store.loadTasks(); err != nil {
+	backend, err := OpenBackend(backendName, dataDir)
+	if err != nil {
 		return nil, err
 	}
 
-	return store, nil
+	return NewTaskStoreWithBackend(backend), nil
 }
 
-// getDataDir determines the data directory (.uni in git repo or ~/.uni)
-func getDataDir() (string, error) {
-	// Check if we're in a git repository and have a .uni directory
-	if _, err := os.Stat(".git"); err == nil {
-		uniDir := ".uni"
-		if _, err := os.Stat(uniDir); err == nil {
-			return uniDir, nil
-		}
-	}
+// NewTaskStoreWithBackend creates a task store on top of an existing backend
+func NewTaskStoreWithBackend(backend Backend) *TaskStore {
+	return &TaskStore{backend: backend, workflow: DefaultWorkflow()}
+}
 
-	// Use ~/.uni as default
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(homeDir, ".uni"), nil
+// SetWorkflow replaces the workflow of the store, which defaults to DefaultWorkflow
+func (ts *TaskStore) SetWorkflow(w *Workflow) {
+	ts.workflow = w
 }
 
-// ensureDataDir creates the data directory if it doesn't exist
-func (ts *TaskStore) ensureDataDir() error {
-	return os.MkdirAll(ts.dataDir, 0755)
+// Workflow returns the workflow the tasks of the store follow
+func (ts *TaskStore) Workflow() *Workflow {
+	return ts.workflow
 }
 
-// getTasksFile returns the path to the tasks.json file
-func (ts *TaskStore) getTasksFile() string {
-	return filepath.Join(ts.dataDir, "tasks.json")
+// Backend returns the storage backend behind the store
+func (ts *TaskStore) Backend() Backend {
+	return ts.backend
 }
 
-// loadTasks loads tasks from the JSON file
-func (ts *TaskStore) loadTasks() error {
-	tasksFile := ts.getTasksFile()
+// OnChange registers a hook that is called after every committed change made through the store
+func (ts *TaskStore) OnChange(hook ChangeHook) {
+	ts.hooks = append(ts.hooks, hook)
+}
 
-	if _, err := os.Stat(tasksFile); os.IsNotExist(err) {
-		// File doesn't exist, start with empty slice
-		ts.tasks = []Task{}
-		return nil
+// mutate runs fn in a backend transaction and hands the tasks it wrote to the change hooks
+func (ts *TaskStore) mutate(fn func(tx Tx) error) error {
+	if len(ts.hooks) == 0 {
+		return ts.backend.Mutate(fn)
 	}
 
-	data, err := os.ReadFile(tasksFile)
+	var rec *recordingTx
+	err := ts.backend.Mutate(func(tx Tx) error {
+		rec = &recordingTx{Tx: tx, written: map[int]Task{}}
+		return fn(rec)
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(data) == 0 {
-		ts.tasks = []Task{}
-		return nil
+	changed := make([]Task, 0, len(rec.order))
+	for _, id := range rec.order {
+		changed = append(changed, rec.written[id])
+	}
+	for _, hook := range ts.hooks {
+		hook(changed)
 	}
+	return nil
+}
 
-	return json.Unmarshal(data, &ts.tasks)
+// recordingTx remembers the last version of every task put through it
+type recordingTx struct {
+	Tx
+	written map[int]Task
+	order   []int
 }
 
-// saveTasks saves tasks to the JSON file
-func (ts *TaskStore) saveTasks() error {
-	data, err := json.MarshalIndent(ts.tasks, "", "  ")
-	if err != nil {
+func (tx *recordingTx) Put(task *Task) error {
+	if err := tx.Tx.Put(task); err != nil {
 		return err
 	}
+	if _, ok := tx.written[task.ID]; !ok {
+		tx.order = append(tx.order, task.ID)
+	}
+	tx.written[task.ID] = task.clone()
+	return nil
+}
 
-	return os.WriteFile(ts.getTasksFile(), data, 0644)
+// Close releases the resources held by the store's backend
+func (ts *TaskStore) Close() error {
+	return ts.backend.Close()
 }
 
-// getNextID returns the next available ID
-func (ts *TaskStore) getNextID() int {
-	maxID := 0
-	for _, task := range ts.tasks {
-		if task.ID > maxID {
-			maxID = task.ID
-		}
-	}
-	return maxID + 1
+// ensureDataDir creates the data directory if it doesn't exist
+func ensureDataDir(dataDir string) error {
+	return os.MkdirAll(dataDir, 0755)
 }
 
 // AddTask adds a new task
 func (ts *TaskStore) AddTask(name, description string) (*Task, error) {
-	now := time.Now()
-	task := Task{
-		ID:          ts.getNextID(),
-		Name:        name,
-		Description: description,
-		Status:      StatusOpen,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+	return ts.CreateTask(TaskSpec{Name: name, Description: description})
+}
+
+// CreateTask adds a new task described by spec
+func (ts *TaskStore) CreateTask(spec TaskSpec) (*Task, error) {
+	tags, err := NormalizeTags(spec.Tags)
+	if err != nil {
+		return nil, err
 	}
+	priority, err := ParsePriority(string(spec.Priority))
+	if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	err = ts.mutate(func(tx Tx) error {
+		if spec.Parent != 0 {
+			if _, err := tx.Get(spec.Parent); err != nil {
+				return fmt.Errorf("invalid parent: %w", err)
+			}
+		}
 
-	ts.tasks = append(ts.tasks, task)
+		id, err := tx.NextID()
+		if err != nil {
+			return err
+		}
 
-	if err := ts.saveTasks(); err != nil {
+		now := time.Now()
+		task = Task{
+			ID:          id,
+			Name:        spec.Name,
+			Description: spec.Description,
+			Status:      ts.workflow.Initial(),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Parent:      spec.Parent,
+			Tags:        tags,
+			Priority:    priority,
+			DueAt:       spec.DueAt,
+		}
+		return tx.Put(&task)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -154,12 +278,7 @@ func (ts *TaskStore) AddTask(name, description string) (*Task, error) {
 
 // GetTask gets a task by ID
 func (ts *TaskStore) GetTask(id int) (*Task, error) {
-	for i, task := range ts.tasks {
-		if task.ID == id {
-			return &ts.tasks[i], nil
-		}
-	}
-	return nil, fmt.Errorf("task with ID %d not found", id)
+	return ts.backend.Get(id)
 }
 
 // ListTasks returns all tasks sorted by ID
@@ -169,64 +288,361 @@ func (ts *TaskStore) ListTasks() []Task {
 
 // ListTasksWithFilter returns tasks with optional filtering
 func (ts *TaskStore) ListTasksWithFilter(showLeft, showClosed bool) []Task {
-	// Create a copy and sort by ID
-	tasks := make([]Task, len(ts.tasks))
-	copy(tasks, ts.tasks)
-
-	// Apply filters
-	if showLeft || showClosed {
-		filtered := []Task{}
-		for _, task := range tasks {
-			if showLeft && isLeftStatus(task.Status) {
-				filtered = append(filtered, task)
-			} else if showClosed && isClosedStatus(task.Status) {
-				filtered = append(filtered, task)
-			}
-		}
-		tasks = filtered
+	tasks, err := ts.FindTasks(ts.workflow.StatusFilter(showLeft, showClosed))
+	if err != nil {
+		return []Task{}
+	}
+	return tasks
+}
+
+// FindTasks returns the tasks matching filter sorted by ID
+func (ts *TaskStore) FindTasks(filter Filter) ([]Task, error) {
+	tasks, err := ts.backend.List(filter)
+	if err != nil {
+		return nil, err
 	}
 
 	sort.Slice(tasks, func(i, j int) bool {
 		return tasks[i].ID < tasks[j].ID
 	})
 
-	return tasks
+	return tasks, nil
 }
 
-// isLeftStatus returns true if the status represents a "left" (open) task
-func isLeftStatus(status TaskStatus) bool {
-	return status == StatusOpen || status == StatusWorking || status == StatusBlocked
+// UpdateTaskStatus updates the status of a task
+func (ts *TaskStore) UpdateTaskStatus(id int, status TaskStatus) (*Task, error) {
+	return ts.ChangeStatus(id, StatusChange{Status: status})
 }
 
-// isClosedStatus returns true if the status represents a closed task
-func isClosedStatus(status TaskStatus) bool {
-	return status == StatusDone || status == StatusCancel
+// ChangeStatus updates the status of a task and records the transition in its history.
+// The change must be allowed by the workflow of the store.
+func (ts *TaskStore) ChangeStatus(id int, change StatusChange) (*Task, error) {
+	var task *Task
+	err := ts.mutate(func(tx Tx) error {
+		var err error
+		task, err = changeStatus(tx, ts.workflow, id, change, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
 }
 
-// UpdateTaskStatus updates the status of a task
-func (ts *TaskStore) UpdateTaskStatus(id int, status TaskStatus) (*Task, error) {
-	for i, task := range ts.tasks {
-		if task.ID == id {
-			ts.tasks[i].Status = status
-			ts.tasks[i].UpdatedAt = time.Now()
+// StatusResult is the outcome of a batch status change for one task
+type StatusResult struct {
+	ID    int    `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	Task  *Task  `json:"task,omitempty" yaml:"task,omitempty"`
+	// Err is the failure Error describes
+	Err error `json:"-" yaml:"-"`
+}
 
-			if err := ts.saveTasks(); err != nil {
+// ChangeStatuses applies a status change to the tasks with the given IDs and,
+// if filter is set, the tasks it matches, all in one transaction. The first
+// failure, such as a missing ID, fails the whole batch and nothing is changed;
+// with continueOnError the failing tasks are reported in their result instead
+// and none of their changes, including cascaded ones, are kept.
+func (ts *TaskStore) ChangeStatuses(ids []int, filter *Filter, change StatusChange, continueOnError bool) ([]StatusResult, error) {
+	var results []StatusResult
+	err := ts.mutate(func(tx Tx) error {
+		selected := append([]int(nil), ids...)
+		if filter != nil {
+			matches, err := tx.List(*filter)
+			if err != nil {
+				return err
+			}
+			sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+			for _, t := range matches {
+				selected = append(selected, t.ID)
+			}
+		}
+
+		now := time.Now()
+		seen := map[int]bool{}
+		results = make([]StatusResult, 0, len(selected))
+		for _, id := range selected {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			if !continueOnError {
+				task, err := changeStatus(tx, ts.workflow, id, change, now)
+				if err != nil {
+					return err
+				}
+				results = append(results, StatusResult{ID: id, OK: true, Task: task})
+				continue
+			}
+
+			staged := newStagedTx(tx)
+			task, err := changeStatus(staged, ts.workflow, id, change, now)
+			if err == nil {
+				err = staged.commit()
+			}
+			if err != nil {
+				results = append(results, StatusResult{ID: id, Error: err.Error(), Err: err})
+				continue
+			}
+			results = append(results, StatusResult{ID: id, OK: true, Task: task})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// stagedTx holds the writes of one item of a batch until it succeeds, so a
+// failing item leaves nothing behind in the shared transaction
+type stagedTx struct {
+	Tx
+	puts    map[int]Task
+	deletes map[int]bool
+	order   []int
+}
+
+func newStagedTx(tx Tx) *stagedTx {
+	return &stagedTx{Tx: tx, puts: map[int]Task{}, deletes: map[int]bool{}}
+}
+
+func (tx *stagedTx) Get(id int) (*Task, error) {
+	if tx.deletes[id] {
+		return nil, &NotFoundError{ID: id}
+	}
+	if task, ok := tx.puts[id]; ok {
+		task = task.clone()
+		return &task, nil
+	}
+	return tx.Tx.Get(id)
+}
+
+func (tx *stagedTx) List(filter Filter) ([]Task, error) {
+	stored, err := tx.Tx.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []Task{}
+	for _, task := range stored {
+		if _, staged := tx.puts[task.ID]; !staged && !tx.deletes[task.ID] {
+			tasks = append(tasks, task)
+		}
+	}
+	for _, id := range tx.order {
+		if task, ok := tx.puts[id]; ok && filter.Matches(task) {
+			tasks = append(tasks, task.clone())
+		}
+	}
+	return tasks, nil
+}
+
+func (tx *stagedTx) Put(task *Task) error {
+	if _, ok := tx.puts[task.ID]; !ok {
+		tx.order = append(tx.order, task.ID)
+	}
+	tx.puts[task.ID] = task.clone()
+	delete(tx.deletes, task.ID)
+	return nil
+}
+
+func (tx *stagedTx) Delete(id int) error {
+	if _, err := tx.Get(id); err != nil {
+		return err
+	}
+	delete(tx.puts, id)
+	tx.deletes[id] = true
+	return nil
+}
+
+// commit applies the staged writes to the underlying transaction
+func (tx *stagedTx) commit() error {
+	for _, id := range tx.order {
+		if task, ok := tx.puts[id]; ok {
+			if err := tx.Tx.Put(&task); err != nil {
+				return err
+			}
+		}
+	}
+	for id := range tx.deletes {
+		if err := tx.Tx.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changeStatus applies a status change allowed by workflow w inside a transaction
+func changeStatus(tx Tx, w *Workflow, id int, change StatusChange, now time.Time) (*Task, error) {
+	task, err := tx.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !change.UpdatedAt.IsZero() && !task.UpdatedAt.Equal(change.UpdatedAt) {
+		return nil, fmt.Errorf("task #%d was modified since it was read: %w", id, ErrConflict)
+	}
+
+	if err := w.CheckTransition(task.Status, change.Status); err != nil {
+		return nil, fmt.Errorf("task #%d: %w", id, err)
+	}
+
+	previous := task.Status
+	if task.Status != change.Status {
+		task.History = append(task.History, Transition{
+			From:   task.Status,
+			To:     change.Status,
+			At:     now,
+			Reason: change.Reason,
+		})
+	}
+	task.Status = change.Status
+	task.UpdatedAt = now
+
+	// A task with open blockers goes straight back to blocked when it is moved
+	// to another active status
+	if change.Status != StatusBlocked {
+		if err := refreshBlocked(tx, w, task, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Put(task); err != nil {
+		return nil, err
+	}
+
+	// Closing or reopening a task can unblock or block the tasks that depend on it
+	if w.IsClosed(previous) != w.IsClosed(change.Status) {
+		if err := refreshDependents(tx, w, task.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if change.Cascade && w.IsClosed(change.Status) {
+		descendants, err := openDescendants(tx, w, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, descendant := range descendants {
+			cascaded := StatusChange{
+				Status: change.Status,
+				Reason: fmt.Sprintf("closed with parent #%d", id),
+			}
+			if _, err := changeStatus(tx, w, descendant.ID, cascaded, now); err != nil {
 				return nil, err
 			}
+		}
+	}
+
+	return task, nil
+}
 
-			return &ts.tasks[i], nil
+// OpenSubtasks returns the subtasks of a task, at any depth, that are not closed yet
+func (ts *TaskStore) OpenSubtasks(id int) ([]Task, error) {
+	return openDescendants(ts.backend, ts.workflow, id)
+}
+
+// openDescendants walks the subtask tree below id, parents before children,
+// and returns the tasks that are not closed in workflow w
+func openDescendants(r Reader, w *Workflow, id int) ([]Task, error) {
+	children, err := r.List(Filter{Parent: id})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].ID < children[j].ID
+	})
+
+	open := []Task{}
+	for _, child := range children {
+		if !w.IsClosed(child.Status) {
+			open = append(open, child)
+		}
+		below, err := openDescendants(r, w, child.ID)
+		if err != nil {
+			return nil, err
 		}
+		open = append(open, below...)
 	}
-	return nil, fmt.Errorf("task with ID %d not found", id)
+	return open, nil
 }
 
-// UpdateTask updates a task's name and description
+// UpdateTask updates a task's name, description, tags, priority and due date. The task must still carry
+// the UpdatedAt it was read with; if it was changed in the meantime the update
+// fails with ErrConflict instead of overwriting the newer version.
 func (ts *TaskStore) UpdateTask(updatedTask *Task) error {
-	for i, task := range ts.tasks {
-		if task.ID == updatedTask.ID {
-			ts.tasks[i] = *updatedTask
-			return ts.saveTasks()
+	priority, err := ParsePriority(string(updatedTask.Priority))
+	if err != nil {
+		return err
+	}
+	updatedTask.Priority = priority
+
+	tags, err := NormalizeTags(updatedTask.Tags)
+	if err != nil {
+		return err
+	}
+	updatedTask.Tags = tags
+
+	return ts.mutate(func(tx Tx) error {
+		current, err := tx.Get(updatedTask.ID)
+		if err != nil {
+			return err
+		}
+
+		if !current.UpdatedAt.Equal(updatedTask.UpdatedAt) {
+			return fmt.Errorf("task #%d was modified since it was read: %w", updatedTask.ID, ErrConflict)
+		}
+
+		updatedTask.UpdatedAt = time.Now()
+		stored := *updatedTask
+		stored.Project = ""
+		stored.Store = ""
+		return tx.Put(&stored)
+	})
+}
+
+// DeleteTask removes a task. If updatedAt is set the deletion fails with ErrConflict
+// when the task was changed since; tasks that are the parent of or block other
+// tasks cannot be deleted.
+func (ts *TaskStore) DeleteTask(id int, updatedAt time.Time) error {
+	return ts.mutate(func(tx Tx) error {
+		task, err := tx.Get(id)
+		if err != nil {
+			return err
 		}
+		if !updatedAt.IsZero() && !task.UpdatedAt.Equal(updatedAt) {
+			return fmt.Errorf("task #%d was modified since it was read: %w", id, ErrConflict)
+		}
+
+		subtasks, err := tx.List(Filter{Parent: id})
+		if err != nil {
+			return err
+		}
+		if len(subtasks) > 0 {
+			return fmt.Errorf("task #%d has subtasks %v: %w", id, taskIDs(subtasks), ErrInUse)
+		}
+
+		dependents, err := tx.List(Filter{BlockedBy: id})
+		if err != nil {
+			return err
+		}
+		if len(dependents) > 0 {
+			return fmt.Errorf("task #%d blocks tasks %v: %w", id, taskIDs(dependents), ErrInUse)
+		}
+
+		return tx.Delete(id)
+	})
+}
+
+func taskIDs(tasks []Task) []int {
+	ids := make([]int, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
 	}
-	return fmt.Errorf("task with ID %d not found", updatedTask.ID)
+	sort.Ints(ids)
+	return ids
 }
@@ -1,8 +1,10 @@
 package task
 
 import (
+	"errors"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestTaskStore_AddTask(t *testing.T) {
@@ -13,11 +15,8 @@ func TestTaskStore_AddTask(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Create a task store with the temp directory
-	store := &TaskStore{
-		dataDir: tempDir,
-		tasks:   []Task{},
-	}
+	// Create a task store backed by the temp directory
+	store := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
 
 	// Test adding a task
 	task, err := store.AddTask("Test Task", "Test Description")
@@ -60,10 +59,7 @@ func TestTaskStore_GetTask(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	store := &TaskStore{
-		dataDir: tempDir,
-		tasks:   []Task{},
-	}
+	store := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
 
 	// Add a task
 	addedTask, err := store.AddTask("Test Task", "Test Description")
@@ -96,10 +92,7 @@ func TestTaskStore_UpdateTaskStatus(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	store := &TaskStore{
-		dataDir: tempDir,
-		tasks:   []Task{},
-	}
+	store := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
 
 	// Add a task
 	task, err := store.AddTask("Test Task", "Test Description")
@@ -128,6 +121,131 @@ func TestTaskStore_UpdateTaskStatus(t *testing.T) {
 	}
 }
 
+func TestTaskStore_ChangeStatusHistory(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+
+	task, err := store.AddTask("Test Task", "")
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	if _, err := store.ChangeStatus(task.ID, StatusChange{Status: StatusWorking}); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	if _, err := store.ChangeStatus(task.ID, StatusChange{Status: StatusBlocked, Reason: "waiting on review"}); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	// Setting the same status again is not a transition
+	if _, err := store.ChangeStatus(task.ID, StatusChange{Status: StatusBlocked}); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+
+	retrievedTask, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+
+	if len(retrievedTask.History) != 2 {
+		t.Fatalf("Expected 2 transitions, got %d", len(retrievedTask.History))
+	}
+
+	first, second := retrievedTask.History[0], retrievedTask.History[1]
+	if first.From != StatusOpen || first.To != StatusWorking {
+		t.Errorf("Expected open -> working, got %s -> %s", first.From, first.To)
+	}
+	if second.From != StatusWorking || second.To != StatusBlocked || second.Reason != "waiting on review" {
+		t.Errorf("Unexpected second transition: %+v", second)
+	}
+	if second.At.Before(first.At) {
+		t.Error("Expected transitions in chronological order")
+	}
+}
+
+func TestTaskStore_ChangeStatuses(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewJSONBackend(t.TempDir()))
+	for _, spec := range []TaskSpec{
+		{Name: "One"}, {Name: "Two", Tags: []string{"sprint"}}, {Name: "Three", Tags: []string{"sprint"}}, {Name: "Four"},
+	} {
+		if _, err := store.CreateTask(spec); err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+	}
+	statuses := func() []TaskStatus {
+		tasks, _ := store.FindTasks(Filter{})
+		var got []TaskStatus
+		for _, task := range tasks {
+			got = append(got, task.Status)
+		}
+		return got
+	}
+
+	// A missing ID fails the whole batch
+	if _, err := store.ChangeStatuses([]int{1, 9, 4}, nil, StatusChange{Status: StatusDone}, false); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if got := statuses(); got[0] != StatusOpen || got[3] != StatusOpen {
+		t.Fatalf("Expected nothing to change, got %v", got)
+	}
+
+	results, err := store.ChangeStatuses([]int{1, 9, 1}, nil, StatusChange{Status: StatusDone}, true)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v (%v)", results, err)
+	}
+	if !results[0].OK || results[0].Task.Status != StatusDone {
+		t.Errorf("Expected task 1 to be done, got %+v", results[0])
+	}
+	if results[1].OK || results[1].ID != 9 || !errors.Is(results[1].Err, ErrNotFound) || results[1].Error == "" {
+		t.Errorf("Expected task 9 to be reported missing, got %+v", results[1])
+	}
+
+	filter := Filter{Tags: []string{"sprint"}}
+	results, err = store.ChangeStatuses([]int{4}, &filter, StatusChange{Status: StatusWorking}, false)
+	if err != nil || len(results) != 3 || results[0].ID != 4 || results[1].ID != 2 || results[2].ID != 3 {
+		t.Fatalf("Expected tasks 4, 2 and 3, got %+v (%v)", results, err)
+	}
+	if got := statuses(); got[0] != StatusDone || got[1] != StatusWorking || got[2] != StatusWorking || got[3] != StatusWorking {
+		t.Errorf("Unexpected statuses %v", got)
+	}
+}
+
+func TestTaskStore_ChangeStatusesDiscardsFailedItems(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewJSONBackend(t.TempDir()))
+	store.SetWorkflow(&Workflow{
+		Statuses: []StatusDef{
+			{Name: StatusOpen, Kind: KindActive},
+			{Name: StatusWorking, Kind: KindActive},
+			{Name: StatusDone, Kind: KindClosed},
+			{Name: StatusCancel, Kind: KindClosed},
+		},
+		Transitions: map[TaskStatus][]TaskStatus{StatusOpen: {StatusWorking, StatusCancel}},
+	})
+	parent, _ := store.CreateTask(TaskSpec{Name: "Parent"})
+	store.CreateTask(TaskSpec{Name: "Open child", Parent: parent.ID})
+	other, _ := store.CreateTask(TaskSpec{Name: "Other"})
+	for _, id := range []int{parent.ID, other.ID} {
+		if _, err := store.ChangeStatus(id, StatusChange{Status: StatusWorking}); err != nil {
+			t.Fatalf("Failed to start task %d: %v", id, err)
+		}
+	}
+
+	// The open child cannot move to done, so the cascade fails half way
+	results, err := store.ChangeStatuses([]int{parent.ID, other.ID}, nil, StatusChange{Status: StatusDone, Cascade: true}, true)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v (%v)", results, err)
+	}
+	if results[0].OK || !errors.Is(results[0].Err, ErrInvalidTransition) || !results[1].OK {
+		t.Errorf("Expected only the parent to fail, got %+v", results)
+	}
+
+	stored, _ := store.GetTask(parent.ID)
+	if stored.Status != StatusWorking || len(stored.History) != 1 {
+		t.Errorf("Expected the failed parent to be left as it was, got %s with %d transitions", stored.Status, len(stored.History))
+	}
+	if stored, _ := store.GetTask(other.ID); stored.Status != StatusDone {
+		t.Errorf("Expected the other task to be done, got %s", stored.Status)
+	}
+}
+
 func TestTaskStore_ListTasksWithFilter(t *testing.T) {
 	// Create a temporary directory for testing
 	tempDir, err := os.MkdirTemp("", "uni-test")
@@ -136,10 +254,7 @@ func TestTaskStore_ListTasksWithFilter(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	store := &TaskStore{
-		dataDir: tempDir,
-		tasks:   []Task{},
-	}
+	store := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
 
 	// Add tasks with different statuses
 	_, _ = store.AddTask("Open Task", "")
@@ -170,33 +285,33 @@ func TestTaskStore_ListTasksWithFilter(t *testing.T) {
 	}
 }
 
-func TestIsLeftStatus(t *testing.T) {
+func TestWorkflow_IsActive(t *testing.T) {
 	leftStatuses := []TaskStatus{StatusOpen, StatusWorking, StatusBlocked}
 	for _, status := range leftStatuses {
-		if !isLeftStatus(status) {
+		if !DefaultWorkflow().IsActive(status) {
 			t.Errorf("Expected %s to be a left status", status)
 		}
 	}
 
 	nonLeftStatuses := []TaskStatus{StatusDone, StatusCancel}
 	for _, status := range nonLeftStatuses {
-		if isLeftStatus(status) {
+		if DefaultWorkflow().IsActive(status) {
 			t.Errorf("Expected %s to not be a left status", status)
 		}
 	}
 }
 
-func TestIsClosedStatus(t *testing.T) {
+func TestWorkflow_IsClosed(t *testing.T) {
 	closedStatuses := []TaskStatus{StatusDone, StatusCancel}
 	for _, status := range closedStatuses {
-		if !isClosedStatus(status) {
+		if !DefaultWorkflow().IsClosed(status) {
 			t.Errorf("Expected %s to be a closed status", status)
 		}
 	}
 
 	nonClosedStatuses := []TaskStatus{StatusOpen, StatusWorking, StatusBlocked}
 	for _, status := range nonClosedStatuses {
-		if isClosedStatus(status) {
+		if DefaultWorkflow().IsClosed(status) {
 			t.Errorf("Expected %s to not be a closed status", status)
 		}
 	}
@@ -211,10 +326,7 @@ func TestTaskStore_LoadAndSaveTasks(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Create first store and add tasks
-	store1 := &TaskStore{
-		dataDir: tempDir,
-		tasks:   []Task{},
-	}
+	store1 := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
 
 	task1, err := store1.AddTask("Task 1", "Description 1")
 	if err != nil {
@@ -227,18 +339,15 @@ func TestTaskStore_LoadAndSaveTasks(t *testing.T) {
 	}
 
 	// Create second store and load tasks
-	store2 := &TaskStore{
-		dataDir: tempDir,
-		tasks:   []Task{},
-	}
+	store2 := NewTaskStoreWithBackend(NewJSONBackend(tempDir))
 
-	err = store2.loadTasks()
+	loaded, err := store2.Backend().Load()
 	if err != nil {
 		t.Fatalf("Failed to load tasks: %v", err)
 	}
 
-	if len(store2.tasks) != 2 {
-		t.Errorf("Expected 2 tasks, got %d", len(store2.tasks))
+	if len(loaded) != 2 {
+		t.Errorf("Expected 2 tasks, got %d", len(loaded))
 	}
 
 	// Verify tasks were loaded correctly
@@ -260,3 +369,115 @@ func TestTaskStore_LoadAndSaveTasks(t *testing.T) {
 		t.Errorf("Expected task name '%s', got '%s'", task2.Name, loadedTask2.Name)
 	}
 }
+
+func TestTaskStore_Subtasks(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+
+	epic, _ := store.AddTask("Epic", "")
+	child, err := store.CreateTask(TaskSpec{Name: "Child", Parent: epic.ID})
+	if err != nil {
+		t.Fatalf("Failed to add subtask: %v", err)
+	}
+	grandchild, _ := store.CreateTask(TaskSpec{Name: "Grandchild", Parent: child.ID})
+
+	if child.Parent != epic.ID {
+		t.Errorf("Expected parent %d, got %d", epic.ID, child.Parent)
+	}
+	if _, err := store.CreateTask(TaskSpec{Name: "Orphan", Parent: 999}); err == nil {
+		t.Error("Expected error when parent does not exist")
+	}
+
+	// Closing a child leaves its own open subtasks reachable from the epic
+	if _, err := store.UpdateTaskStatus(child.ID, StatusDone); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	open, err := store.OpenSubtasks(epic.ID)
+	if err != nil {
+		t.Fatalf("Failed to list open subtasks: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != grandchild.ID {
+		t.Errorf("Expected grandchild to be the only open subtask, got %v", open)
+	}
+
+	if _, err := store.ChangeStatus(epic.ID, StatusChange{Status: StatusCancel, Cascade: true}); err != nil {
+		t.Fatalf("Failed to cascade status: %v", err)
+	}
+	cascaded, _ := store.GetTask(grandchild.ID)
+	if cascaded.Status != StatusCancel {
+		t.Errorf("Expected grandchild to be cancelled, got %s", cascaded.Status)
+	}
+	if closedChild, _ := store.GetTask(child.ID); closedChild.Status != StatusDone {
+		t.Errorf("Expected already closed child to stay done, got %s", closedChild.Status)
+	}
+}
+
+func TestTaskStore_OnChange(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+
+	var calls [][]Task
+	store.OnChange(func(changed []Task) {
+		calls = append(calls, changed)
+	})
+
+	if _, err := store.AddTask("Blocker", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if _, err := store.AddTask("Dependent", ""); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if _, err := store.LinkTask(2, []int{1}); err != nil {
+		t.Fatalf("Failed to link tasks: %v", err)
+	}
+
+	// Closing the blocker also writes the unblocked dependent
+	calls = nil
+	if _, err := store.UpdateTaskStatus(1, StatusDone); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	if len(calls) != 1 || len(calls[0]) != 2 || calls[0][0].ID != 1 || calls[0][1].ID != 2 {
+		t.Fatalf("Expected one call with tasks 1 and 2, got %v", calls)
+	}
+	if calls[0][1].Status != StatusOpen {
+		t.Errorf("Expected the hook to see the final version of task 2, got %s", calls[0][1].Status)
+	}
+
+	// Failed changes are not reported
+	calls = nil
+	if _, err := store.UpdateTaskStatus(99, StatusDone); err == nil {
+		t.Fatal("Expected updating a missing task to fail")
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no hook call for a failed change, got %v", calls)
+	}
+}
+
+func TestTaskStore_DeleteTask(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+	parent, _ := store.AddTask("Parent", "")
+	child, _ := store.CreateTask(TaskSpec{Name: "Child", Parent: parent.ID})
+	blocker, _ := store.AddTask("Blocker", "")
+	if _, err := store.LinkTask(child.ID, []int{blocker.ID}); err != nil {
+		t.Fatalf("Failed to link blocker: %v", err)
+	}
+
+	if err := store.DeleteTask(parent.ID, time.Time{}); !errors.Is(err, ErrInUse) {
+		t.Errorf("Expected deleting a parent to fail with ErrInUse, got %v", err)
+	}
+	if err := store.DeleteTask(blocker.ID, time.Time{}); !errors.Is(err, ErrInUse) {
+		t.Errorf("Expected deleting a blocker to fail with ErrInUse, got %v", err)
+	}
+
+	current, _ := store.GetTask(child.ID)
+	if err := store.DeleteTask(child.ID, current.UpdatedAt.Add(-time.Second)); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected deleting with a stale version to fail with ErrConflict, got %v", err)
+	}
+	if err := store.DeleteTask(child.ID, current.UpdatedAt); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if err := store.DeleteTask(child.ID, time.Time{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected deleting a missing task to fail with ErrNotFound, got %v", err)
+	}
+	if err := store.DeleteTask(parent.ID, time.Time{}); err != nil {
+		t.Errorf("Expected parent without subtasks to be deletable, got %v", err)
+	}
+}
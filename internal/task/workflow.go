@@ -0,0 +1,168 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StatusKind says whether a status counts as active (left) or closed
+type StatusKind string
+
+const (
+	KindActive StatusKind = "active"
+	KindClosed StatusKind = "closed"
+)
+
+// ColorNames lists the colors a status can be rendered in
+var ColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white", "gray"}
+
+// ErrInvalidTransition is returned when the workflow does not allow a status change
+var ErrInvalidTransition = errors.New("transition not allowed by workflow")
+
+// StatusDef declares a single status of a workflow
+type StatusDef struct {
+	Name  TaskStatus `json:"name" yaml:"name"`
+	Kind  StatusKind `json:"kind" yaml:"kind"`
+	Color string     `json:"color,omitempty" yaml:"color,omitempty"`
+}
+
+// Workflow declares the statuses a task can have and which transitions are allowed.
+// The first status is the one new tasks start in. A status missing from
+// Transitions may move to any status; an empty list makes it final.
+type Workflow struct {
+	Statuses    []StatusDef                 `json:"statuses" yaml:"statuses"`
+	Transitions map[TaskStatus][]TaskStatus `json:"transitions,omitempty" yaml:"transitions,omitempty"`
+}
+
+// DefaultWorkflow returns the built-in workflow: open, working, blocked, done and cancel
+// with every transition allowed
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []StatusDef{
+			{Name: StatusOpen, Kind: KindActive, Color: "yellow"},
+			{Name: StatusWorking, Kind: KindActive, Color: "blue"},
+			{Name: StatusBlocked, Kind: KindActive, Color: "magenta"},
+			{Name: StatusDone, Kind: KindClosed, Color: "green"},
+			{Name: StatusCancel, Kind: KindClosed, Color: "red"},
+		},
+	}
+}
+
+// Validate checks that the workflow is well formed
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return fmt.Errorf("workflow must declare at least one status")
+	}
+
+	seen := map[TaskStatus]bool{}
+	for _, def := range w.Statuses {
+		if def.Name == "" {
+			return fmt.Errorf("workflow status without a name")
+		}
+		if seen[def.Name] {
+			return fmt.Errorf("workflow status %s is declared twice", def.Name)
+		}
+		seen[def.Name] = true
+
+		if def.Kind != KindActive && def.Kind != KindClosed {
+			return fmt.Errorf("workflow status %s has invalid kind %q (use %s or %s)", def.Name, def.Kind, KindActive, KindClosed)
+		}
+		if def.Color != "" && !validColor(def.Color) {
+			return fmt.Errorf("workflow status %s has unknown color %q. Valid colors: %v", def.Name, def.Color, ColorNames)
+		}
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("workflow transition from unknown status %s", from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("workflow transition from %s to unknown status %s", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+func validColor(color string) bool {
+	for _, name := range ColorNames {
+		if color == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the definition of a status
+func (w *Workflow) Status(status TaskStatus) (StatusDef, bool) {
+	for _, def := range w.Statuses {
+		if def.Name == status {
+			return def, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+// StatusNames returns all declared statuses in order
+func (w *Workflow) StatusNames() []TaskStatus {
+	names := make([]TaskStatus, len(w.Statuses))
+	for i, def := range w.Statuses {
+		names[i] = def.Name
+	}
+	return names
+}
+
+// Initial returns the status new tasks start in
+func (w *Workflow) Initial() TaskStatus {
+	return w.Statuses[0].Name
+}
+
+// IsActive returns true if the status counts as active (left)
+func (w *Workflow) IsActive(status TaskStatus) bool {
+	def, ok := w.Status(status)
+	return ok && def.Kind == KindActive
+}
+
+// IsClosed returns true if the status counts as closed
+func (w *Workflow) IsClosed(status TaskStatus) bool {
+	def, ok := w.Status(status)
+	return ok && def.Kind == KindClosed
+}
+
+// StatusFilter builds a filter for left and/or closed tasks; with neither set it matches everything
+func (w *Workflow) StatusFilter(showLeft, showClosed bool) Filter {
+	filter := Filter{}
+	if !showLeft && !showClosed {
+		return filter
+	}
+
+	for _, status := range w.StatusNames() {
+		if showLeft && w.IsActive(status) || showClosed && w.IsClosed(status) {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	return filter
+}
+
+// CheckTransition returns an error unless a task may move from one status to another
+func (w *Workflow) CheckTransition(from, to TaskStatus) error {
+	if _, ok := w.Status(to); !ok {
+		return fmt.Errorf("unknown status %s. Valid statuses: %v", to, w.StatusNames())
+	}
+
+	if from == to {
+		return nil
+	}
+
+	targets, restricted := w.Transitions[from]
+	if !restricted {
+		return nil
+	}
+	for _, target := range targets {
+		if target == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot move from %s to %s (allowed: %v): %w", from, to, targets, ErrInvalidTransition)
+}
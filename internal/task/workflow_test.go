@@ -0,0 +1,111 @@
+package task
+
+import (
+	"errors"
+	"testing"
+)
+
+func reviewWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []StatusDef{
+			{Name: "todo", Kind: KindActive, Color: "yellow"},
+			{Name: "review", Kind: KindActive, Color: "cyan"},
+			{Name: "qa", Kind: KindActive},
+			{Name: "shipped", Kind: KindClosed, Color: "green"},
+		},
+		Transitions: map[TaskStatus][]TaskStatus{
+			"todo":    {"review"},
+			"review":  {"todo", "qa"},
+			"shipped": {},
+		},
+	}
+}
+
+func TestDefaultWorkflow_AllowsEverything(t *testing.T) {
+	w := DefaultWorkflow()
+	if err := w.Validate(); err != nil {
+		t.Fatalf("Expected default workflow to be valid: %v", err)
+	}
+
+	for _, from := range w.StatusNames() {
+		for _, to := range w.StatusNames() {
+			if err := w.CheckTransition(from, to); err != nil {
+				t.Errorf("Expected %s -> %s to be allowed: %v", from, to, err)
+			}
+		}
+	}
+
+	if w.Initial() != StatusOpen {
+		t.Errorf("Expected new tasks to start open, got %s", w.Initial())
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	if err := reviewWorkflow().Validate(); err != nil {
+		t.Fatalf("Expected workflow to be valid: %v", err)
+	}
+
+	invalid := map[string]*Workflow{
+		"empty":          {},
+		"bad kind":       {Statuses: []StatusDef{{Name: "a", Kind: "maybe"}}},
+		"duplicate":      {Statuses: []StatusDef{{Name: "a", Kind: KindActive}, {Name: "a", Kind: KindClosed}}},
+		"bad color":      {Statuses: []StatusDef{{Name: "a", Kind: KindActive, Color: "plaid"}}},
+		"unknown target": {Statuses: []StatusDef{{Name: "a", Kind: KindActive}}, Transitions: map[TaskStatus][]TaskStatus{"a": {"b"}}},
+		"unknown source": {Statuses: []StatusDef{{Name: "a", Kind: KindActive}}, Transitions: map[TaskStatus][]TaskStatus{"b": {"a"}}},
+	}
+	for name, w := range invalid {
+		if err := w.Validate(); err == nil {
+			t.Errorf("Expected %s workflow to be invalid", name)
+		}
+	}
+}
+
+func TestWorkflow_CheckTransition(t *testing.T) {
+	w := reviewWorkflow()
+
+	if err := w.CheckTransition("todo", "review"); err != nil {
+		t.Errorf("Expected todo -> review to be allowed: %v", err)
+	}
+	if err := w.CheckTransition("todo", "qa"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected todo -> qa to be refused, got %v", err)
+	}
+	if err := w.CheckTransition("qa", "todo"); err != nil {
+		t.Errorf("Expected unrestricted qa -> todo to be allowed: %v", err)
+	}
+	if err := w.CheckTransition("shipped", "todo"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected final status to refuse transitions, got %v", err)
+	}
+	if err := w.CheckTransition("todo", "nope"); err == nil {
+		t.Error("Expected unknown status to be refused")
+	}
+}
+
+func TestTaskStore_CustomWorkflow(t *testing.T) {
+	store := NewTaskStoreWithBackend(NewMemoryBackend())
+	store.SetWorkflow(reviewWorkflow())
+
+	task, err := store.AddTask("Feature", "")
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if task.Status != "todo" {
+		t.Errorf("Expected new task to start in todo, got %s", task.Status)
+	}
+
+	if _, err := store.UpdateTaskStatus(task.ID, "qa"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected todo -> qa to be refused, got %v", err)
+	}
+	if _, err := store.UpdateTaskStatus(task.ID, StatusDone); err == nil {
+		t.Error("Expected status outside the workflow to be refused")
+	}
+	if _, err := store.UpdateTaskStatus(task.ID, "review"); err != nil {
+		t.Fatalf("Failed to move task to review: %v", err)
+	}
+
+	if left := store.ListTasksWithFilter(true, false); len(left) != 1 {
+		t.Errorf("Expected review to count as left, got %d tasks", len(left))
+	}
+	if w := store.Workflow(); !w.IsClosed("shipped") || w.IsActive("shipped") {
+		t.Error("Expected shipped to count as closed")
+	}
+}
@@ -0,0 +1,399 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mad01/uni/internal/query"
+	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
+)
+
+// pollInterval is how often the data directory is checked for changes made by other processes
+const pollInterval = time.Second
+
+type mode int
+
+const (
+	modeBoard mode = iota
+	modeFilter
+	modeEditName
+	modeEditDescription
+)
+
+// tickMsg triggers a check of the data directory
+type tickMsg time.Time
+
+// Board is a kanban board with one column per workflow status
+type Board struct {
+	client  *uni.Client
+	dataDir string
+	stamp   time.Time
+
+	statuses []task.TaskStatus
+	columns  [][]task.Task
+	col      int
+	rows     []int
+	offsets  []int
+
+	filterText string
+	filter     *query.Query
+
+	mode    mode
+	input   lineInput
+	editing task.Task
+
+	message  string
+	errorMsg bool
+
+	width, height int
+}
+
+// NewBoard loads the tasks of client into a board, showing only those matching filter.
+// Changes to the files in dataDir are picked up while the board runs; an empty
+// dataDir disables live reload.
+func NewBoard(client *uni.Client, dataDir, filter string) (*Board, error) {
+	q, err := query.ParseWorkflow(filter, time.Now(), client.Workflow())
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Board{client: client, dataDir: dataDir, filterText: filter, filter: q, width: 80, height: 24}
+	b.stamp = dirStamp(dataDir)
+	if err := b.reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Run shows the board full-screen until the user quits
+func Run(b *Board) error {
+	_, err := tea.NewProgram(b, tea.WithAltScreen()).Run()
+	return err
+}
+
+// Init starts watching the data directory
+func (b *Board) Init() tea.Cmd {
+	return tick()
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(pollInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// Update handles keys, window resizes and reload ticks
+func (b *Board) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.width, b.height = msg.Width, msg.Height
+		b.scrollToSelection()
+	case tickMsg:
+		if b.dataDir != "" {
+			if stamp := dirStamp(b.dataDir); !stamp.Equal(b.stamp) {
+				b.stamp = stamp
+				b.reloadOrReport()
+			}
+		}
+		return b, tick()
+	case tea.KeyMsg:
+		return b, b.handleKey(msg)
+	}
+	return b, nil
+}
+
+func (b *Board) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if b.mode != modeBoard {
+		b.handleInputKey(msg)
+		return nil
+	}
+
+	// Keys typed quickly can arrive as one message; the board handles them one by one
+	// and passes whatever follows a key that opens an input on to that input
+	if msg.Type == tea.KeyRunes && len(msg.Runes) > 1 && !msg.Paste {
+		var cmds []tea.Cmd
+		for i, r := range msg.Runes {
+			if b.mode != modeBoard {
+				b.input.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: msg.Runes[i:]})
+				break
+			}
+			cmds = append(cmds, b.handleBoardKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: msg.Alt}))
+		}
+		return tea.Batch(cmds...)
+	}
+	return b.handleBoardKey(msg)
+}
+
+func (b *Board) handleBoardKey(msg tea.KeyMsg) tea.Cmd {
+	b.message = ""
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "left", "h":
+		b.selectColumn(b.col - 1)
+	case "right", "l":
+		b.selectColumn(b.col + 1)
+	case "up", "k":
+		b.selectRow(b.rows[b.col] - 1)
+	case "down", "j":
+		b.selectRow(b.rows[b.col] + 1)
+	case "home", "g":
+		b.selectRow(0)
+	case "end", "G":
+		b.selectRow(len(b.columns[b.col]) - 1)
+	case "shift+left", "H", "<":
+		b.moveSelected(b.col - 1)
+	case "shift+right", "L", ">":
+		b.moveSelected(b.col + 1)
+	case "e", "enter":
+		if t := b.Selected(); t != nil {
+			b.editing = *t
+			b.mode = modeEditName
+			b.input = newLineInput(fmt.Sprintf("Name of #%d: ", t.ID), t.Name, false)
+		}
+	case "d":
+		if t := b.Selected(); t != nil {
+			b.editing = *t
+			b.mode = modeEditDescription
+			b.input = newLineInput(fmt.Sprintf("Description of #%d (alt+enter for a new line): ", t.ID), t.Description, true)
+		}
+	case "/":
+		b.mode = modeFilter
+		b.input = newLineInput("Filter: ", b.filterText, false)
+	case "esc":
+		if b.filterText != "" {
+			b.applyFilter("")
+		}
+	case "r":
+		if b.reloadOrReport() {
+			b.setMessage("Reloaded")
+		}
+	}
+	return nil
+}
+
+func (b *Board) handleInputKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		b.mode = modeBoard
+		b.message = ""
+	case "enter":
+		switch b.mode {
+		case modeFilter:
+			if err := b.applyFilter(b.input.Value()); err != nil {
+				b.setError(err)
+				return
+			}
+		case modeEditName, modeEditDescription:
+			if err := b.saveEdit(b.input.Value()); err != nil {
+				b.setError(err)
+				if !errors.Is(err, task.ErrConflict) {
+					return
+				}
+			}
+		}
+		b.mode = modeBoard
+	default:
+		b.input.update(msg)
+	}
+}
+
+// applyFilter replaces the query narrowing the board
+func (b *Board) applyFilter(text string) error {
+	q, err := query.ParseWorkflow(text, time.Now(), b.client.Workflow())
+	if err != nil {
+		return err
+	}
+
+	b.filterText, b.filter = strings.TrimSpace(text), q
+	b.message = ""
+	return b.reload()
+}
+
+// saveEdit stores the edited name or description of the task being edited
+func (b *Board) saveEdit(value string) error {
+	updated := b.editing
+	if b.mode == modeEditName {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("task name cannot be empty")
+		}
+		updated.Name = value
+	} else {
+		updated.Description = strings.TrimSpace(value)
+	}
+
+	if err := b.client.Update(context.Background(), &updated); err != nil {
+		if errors.Is(err, task.ErrConflict) {
+			b.reloadOrReport()
+			return fmt.Errorf("task #%d was changed elsewhere and has been reloaded; edit it again", updated.ID)
+		}
+		return err
+	}
+
+	b.setMessage(fmt.Sprintf("Updated task #%d", updated.ID))
+	return b.reload()
+}
+
+// moveSelected moves the selected task to the status of column col
+func (b *Board) moveSelected(col int) {
+	t := b.Selected()
+	if t == nil || col < 0 || col >= len(b.statuses) {
+		return
+	}
+
+	status := b.statuses[col]
+	if _, err := b.client.SetStatus(context.Background(), t.ID, task.StatusChange{Status: status}); err != nil {
+		b.setError(err)
+		return
+	}
+
+	b.setMessage(fmt.Sprintf("Moved task #%d to %s", t.ID, status))
+	if err := b.reload(); err != nil {
+		b.setError(err)
+		return
+	}
+	b.selectTask(t.ID)
+}
+
+// Selected returns the task under the cursor, or nil when its column is empty
+func (b *Board) Selected() *task.Task {
+	tasks := b.columns[b.col]
+	if len(tasks) == 0 {
+		return nil
+	}
+	t := tasks[b.rows[b.col]]
+	return &t
+}
+
+func (b *Board) selectColumn(col int) {
+	if col >= 0 && col < len(b.columns) {
+		b.col = col
+	}
+}
+
+func (b *Board) selectRow(row int) {
+	n := len(b.columns[b.col])
+	if row >= n {
+		row = n - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	b.rows[b.col] = row
+	b.scrollToSelection()
+}
+
+// selectTask moves the cursor to the task with the given ID, if it is shown
+func (b *Board) selectTask(id int) {
+	for col, tasks := range b.columns {
+		for row, t := range tasks {
+			if t.ID == id {
+				b.col = col
+				b.selectRow(row)
+				return
+			}
+		}
+	}
+}
+
+// reload reads the tasks again and keeps the cursor on the same task where possible
+func (b *Board) reload() error {
+	selected := 0
+	if b.columns != nil {
+		if t := b.Selected(); t != nil {
+			selected = t.ID
+		}
+	}
+
+	tasks, err := b.client.List(context.Background(), uni.ListOptions{Match: b.filter.Match})
+	if err != nil {
+		return err
+	}
+
+	b.statuses = b.client.Workflow().StatusNames()
+	index := make(map[task.TaskStatus]int, len(b.statuses))
+	for i, status := range b.statuses {
+		index[status] = i
+	}
+	// Tasks in a status the workflow no longer knows get a column at the end
+	for _, t := range tasks {
+		if _, ok := index[t.Status]; !ok {
+			index[t.Status] = len(b.statuses)
+			b.statuses = append(b.statuses, t.Status)
+		}
+	}
+
+	b.columns = make([][]task.Task, len(b.statuses))
+	for _, t := range tasks {
+		i := index[t.Status]
+		b.columns[i] = append(b.columns[i], t)
+	}
+	for i := range b.columns {
+		task.SortTasks(b.columns[i], task.SortByPriority)
+	}
+
+	rows, offsets := b.rows, b.offsets
+	b.rows = make([]int, len(b.columns))
+	b.offsets = make([]int, len(b.columns))
+	copy(b.rows, rows)
+	copy(b.offsets, offsets)
+	if b.col >= len(b.columns) {
+		b.col = len(b.columns) - 1
+	}
+
+	if selected != 0 {
+		b.selectTask(selected)
+	}
+	for col := range b.columns {
+		current := b.col
+		b.col = col
+		b.selectRow(b.rows[col])
+		b.col = current
+	}
+	return nil
+}
+
+// reloadOrReport reloads and shows a failure in the footer; it reports whether the reload worked
+func (b *Board) reloadOrReport() bool {
+	if err := b.reload(); err != nil {
+		b.setError(fmt.Errorf("failed to reload tasks: %v", err))
+		return false
+	}
+	return true
+}
+
+func (b *Board) setMessage(msg string) {
+	b.message, b.errorMsg = msg, false
+}
+
+func (b *Board) setError(err error) {
+	b.message, b.errorMsg = err.Error(), true
+}
+
+// dirStamp returns the latest modification time of the files in dir
+func dirStamp(dir string) time.Time {
+	var latest time.Time
+	if dir == "" {
+		return latest
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return latest
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mad01/uni/internal/task"
+	"github.com/mad01/uni/pkg/uni"
+)
+
+func newTestBoard(t *testing.T, filter string) (*Board, *uni.Client) {
+	t.Helper()
+	ctx := context.Background()
+	client, err := uni.Open(ctx, uni.WithDataDir(t.TempDir()), uni.WithBackend("memory"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	for _, name := range []string{"First", "Second", "Third"} {
+		if _, err := client.Create(ctx, uni.TaskSpec{Name: name}); err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+	}
+	if _, err := client.SetStatus(ctx, 3, uni.StatusChange{Status: task.StatusWorking}); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+
+	board, err := NewBoard(client, "", filter)
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+	return board, client
+}
+
+// press sends keys to the board; names like "down" are special keys, anything else is typed
+func press(b *Board, keys ...string) {
+	special := map[string]tea.KeyType{
+		"up": tea.KeyUp, "down": tea.KeyDown, "left": tea.KeyLeft, "right": tea.KeyRight,
+		"shift+left": tea.KeyShiftLeft, "shift+right": tea.KeyShiftRight,
+		"enter": tea.KeyEnter, "esc": tea.KeyEsc, "ctrl+u": tea.KeyCtrlU, "backspace": tea.KeyBackspace,
+	}
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if kt, ok := special[key]; ok {
+			msg = tea.KeyMsg{Type: kt}
+		}
+		b.Update(msg)
+	}
+}
+
+func TestBoard_Columns(t *testing.T) {
+	board, _ := newTestBoard(t, "")
+
+	if len(board.statuses) != len(board.client.Workflow().StatusNames()) {
+		t.Fatalf("Expected one column per status, got %v", board.statuses)
+	}
+	if len(board.columns[0]) != 2 || len(board.columns[1]) != 1 {
+		t.Errorf("Expected 2 open and 1 working task, got %d and %d", len(board.columns[0]), len(board.columns[1]))
+	}
+
+	press(board, "down")
+	if got := board.Selected(); got == nil || got.ID != 2 {
+		t.Errorf("Expected task #2 to be selected, got %v", got)
+	}
+	press(board, "right")
+	if got := board.Selected(); got == nil || got.ID != 3 {
+		t.Errorf("Expected task #3 to be selected, got %v", got)
+	}
+	press(board, "right")
+	if got := board.Selected(); got != nil {
+		t.Errorf("Expected nothing selected in an empty column, got #%d", got.ID)
+	}
+}
+
+func TestBoard_MoveCard(t *testing.T) {
+	board, client := newTestBoard(t, "")
+
+	press(board, "shift+right")
+	moved, _ := client.Get(context.Background(), 1)
+	if moved.Status != task.StatusWorking {
+		t.Fatalf("Expected task #1 to be working, got %s", moved.Status)
+	}
+	if got := board.Selected(); got == nil || got.ID != 1 || board.col != 1 {
+		t.Errorf("Expected the cursor to follow the moved task, got column %d and %v", board.col, got)
+	}
+
+	press(board, "H")
+	moved, _ = client.Get(context.Background(), 1)
+	if moved.Status != task.StatusOpen {
+		t.Errorf("Expected task #1 to be open again, got %s", moved.Status)
+	}
+
+	press(board, "shift+left")
+	moved, _ = client.Get(context.Background(), 1)
+	if moved.Status != task.StatusOpen || board.col != 0 {
+		t.Errorf("Expected moving left of the first column to do nothing, got %s in column %d", moved.Status, board.col)
+	}
+}
+
+func TestBoard_EditName(t *testing.T) {
+	board, client := newTestBoard(t, "")
+
+	press(board, "e", "ctrl+u", "Renamed", "enter")
+	got, _ := client.Get(context.Background(), 1)
+	if got.Name != "Renamed" {
+		t.Errorf("Expected name to be edited, got %q", got.Name)
+	}
+	if board.mode != modeBoard {
+		t.Errorf("Expected to be back on the board after saving")
+	}
+
+	press(board, "e", "ctrl+u", "enter")
+	if board.mode != modeEditName || !board.errorMsg {
+		t.Errorf("Expected an empty name to be rejected")
+	}
+	press(board, "esc")
+	got, _ = client.Get(context.Background(), 1)
+	if got.Name != "Renamed" {
+		t.Errorf("Expected cancelled edit to keep the name, got %q", got.Name)
+	}
+}
+
+func TestBoard_EditConflict(t *testing.T) {
+	board, client := newTestBoard(t, "")
+
+	press(board, "d", "Notes")
+	changed, _ := client.Get(context.Background(), 1)
+	changed.Name = "Changed elsewhere"
+	if err := client.Update(context.Background(), changed); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	press(board, "enter")
+
+	got, _ := client.Get(context.Background(), 1)
+	if got.Description != "" || !board.errorMsg {
+		t.Errorf("Expected the stale edit to be rejected, got description %q", got.Description)
+	}
+	if sel := board.Selected(); sel == nil || sel.Name != "Changed elsewhere" {
+		t.Errorf("Expected the board to be reloaded after a conflict, got %v", sel)
+	}
+}
+
+func TestBoard_Filter(t *testing.T) {
+	board, _ := newTestBoard(t, "")
+
+	press(board, "/", "name:second", "enter")
+	if len(board.columns[0]) != 1 || board.columns[0][0].ID != 2 || len(board.columns[1]) != 0 {
+		t.Errorf("Expected only task #2 after filtering, got %v", board.columns)
+	}
+	if !strings.Contains(board.View(), "filter: name:second") {
+		t.Errorf("Expected the filter to be shown in the title")
+	}
+
+	press(board, "/", "ctrl+u", "tag:(", "enter")
+	if board.mode != modeFilter || !board.errorMsg {
+		t.Errorf("Expected an invalid query to keep the filter input open with an error")
+	}
+
+	press(board, "esc", "esc")
+	if board.filterText != "" || len(board.columns[0]) != 2 {
+		t.Errorf("Expected esc to clear the filter")
+	}
+}
+
+func TestBoard_TypedKeysArrivingTogether(t *testing.T) {
+	board, _ := newTestBoard(t, "")
+
+	press(board, "j/name:first")
+	if board.mode != modeFilter || board.input.Value() != "name:first" {
+		t.Errorf("Expected keys after / to go to the filter input, got mode %d and %q", board.mode, board.input.Value())
+	}
+	if got := board.Selected(); got == nil || got.ID != 2 {
+		t.Errorf("Expected j to move the cursor before / opened the filter")
+	}
+}
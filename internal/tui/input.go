@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lineInput is a minimal single-line text editor for the footer
+type lineInput struct {
+	prompt string
+	value  []rune
+	cursor int
+	// multiline allows alt+enter to insert a line break, shown as ↵
+	multiline bool
+}
+
+func newLineInput(prompt, value string, multiline bool) lineInput {
+	runes := []rune(value)
+	return lineInput{prompt: prompt, value: runes, cursor: len(runes), multiline: multiline}
+}
+
+// Value returns the edited text
+func (in *lineInput) Value() string {
+	return string(in.value)
+}
+
+// update applies an editing key; enter and esc are handled by the caller
+func (in *lineInput) update(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "left", "ctrl+b":
+		if in.cursor > 0 {
+			in.cursor--
+		}
+	case "right", "ctrl+f":
+		if in.cursor < len(in.value) {
+			in.cursor++
+		}
+	case "home", "ctrl+a":
+		in.cursor = 0
+	case "end", "ctrl+e":
+		in.cursor = len(in.value)
+	case "backspace", "ctrl+h":
+		if in.cursor > 0 {
+			in.value = append(in.value[:in.cursor-1], in.value[in.cursor:]...)
+			in.cursor--
+		}
+	case "delete", "ctrl+d":
+		if in.cursor < len(in.value) {
+			in.value = append(in.value[:in.cursor], in.value[in.cursor+1:]...)
+		}
+	case "ctrl+u":
+		in.value = in.value[in.cursor:]
+		in.cursor = 0
+	case "ctrl+k":
+		in.value = in.value[:in.cursor]
+	case "alt+enter":
+		if in.multiline {
+			in.insert([]rune{'\n'})
+		}
+	default:
+		switch msg.Type {
+		case tea.KeyRunes:
+			runes := msg.Runes
+			if !in.multiline {
+				runes = []rune(strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(string(runes)))
+			}
+			in.insert(runes)
+		case tea.KeySpace:
+			in.insert([]rune{' '})
+		}
+	}
+}
+
+func (in *lineInput) insert(runes []rune) {
+	value := make([]rune, 0, len(in.value)+len(runes))
+	value = append(value, in.value[:in.cursor]...)
+	value = append(value, runes...)
+	value = append(value, in.value[in.cursor:]...)
+	in.value = value
+	in.cursor += len(runes)
+}
+
+// view renders the prompt and the text with a block cursor
+func (in *lineInput) view() string {
+	cursorStyle := lipgloss.NewStyle().Reverse(true)
+	show := func(runes []rune) string {
+		return strings.ReplaceAll(string(runes), "\n", "↵")
+	}
+
+	under := " "
+	if in.cursor < len(in.value) {
+		under = show(in.value[in.cursor : in.cursor+1])
+	}
+	rest := []rune{}
+	if in.cursor < len(in.value) {
+		rest = in.value[in.cursor+1:]
+	}
+	return in.prompt + show(in.value[:in.cursor]) + cursorStyle.Render(under) + show(rest)
+}
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mad01/uni/internal/task"
+)
+
+const (
+	// minColumnWidth is the narrowest a column gets before the board scrolls sideways
+	minColumnWidth = 24
+	// cardHeight is the lines taken by one card, including the gap below it
+	cardHeight = 3
+	// chromeHeight is the lines taken by the title, column headers and footer
+	chromeHeight = 6
+)
+
+// terminalColors maps the workflow color names to the basic ANSI colors
+var terminalColors = map[string]lipgloss.Color{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	mutedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	overdueStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	todayStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+	selectedStyle = lipgloss.NewStyle().Bold(true)
+)
+
+const helpText = "←/→ column  ↑/↓ card  shift+←/→ move  e name  d description  / filter  esc clear filter  r reload  q quit"
+
+// View renders the board
+func (b *Board) View() string {
+	var sb strings.Builder
+
+	title := titleStyle.Render("uni")
+	count := 0
+	for _, tasks := range b.columns {
+		count += len(tasks)
+	}
+	title += mutedStyle.Render(fmt.Sprintf(" · %d tasks", count))
+	if b.filterText != "" {
+		title += mutedStyle.Render(" · filter: ") + b.filterText
+	}
+	sb.WriteString(title + "\n\n")
+
+	first, last := b.visibleColumns()
+	width := b.width / (last - first)
+	rendered := make([]string, 0, last-first)
+	for col := first; col < last; col++ {
+		rendered = append(rendered, b.renderColumn(col, width))
+	}
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, rendered...))
+	sb.WriteString("\n")
+
+	switch {
+	case b.mode != modeBoard:
+		sb.WriteString(b.input.view())
+		if b.errorMsg && b.message != "" {
+			sb.WriteString("  " + errorStyle.Render(b.message))
+		}
+	case b.errorMsg:
+		sb.WriteString(errorStyle.Render(b.message))
+	default:
+		sb.WriteString(b.message)
+	}
+	sb.WriteString("\n" + mutedStyle.Render(ansi.Truncate(helpText, b.width, "…")))
+	return sb.String()
+}
+
+// visibleColumns returns the range of columns that fit, keeping the selected one in view
+func (b *Board) visibleColumns() (int, int) {
+	fit := b.width / minColumnWidth
+	if fit < 1 {
+		fit = 1
+	}
+	if fit >= len(b.columns) {
+		return 0, len(b.columns)
+	}
+
+	first := b.col - fit/2
+	if first < 0 {
+		first = 0
+	}
+	if first+fit > len(b.columns) {
+		first = len(b.columns) - fit
+	}
+	return first, first + fit
+}
+
+// visibleCards is how many cards fit in a column
+func (b *Board) visibleCards() int {
+	n := (b.height - chromeHeight) / cardHeight
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// scrollToSelection adjusts the scroll offset of the selected column so its cursor is shown
+func (b *Board) scrollToSelection() {
+	if len(b.offsets) == 0 {
+		return
+	}
+	row, visible := b.rows[b.col], b.visibleCards()
+	if row < b.offsets[b.col] {
+		b.offsets[b.col] = row
+	}
+	if row >= b.offsets[b.col]+visible {
+		b.offsets[b.col] = row - visible + 1
+	}
+}
+
+func (b *Board) renderColumn(col, width int) string {
+	status := b.statuses[col]
+	workflow := b.client.Workflow()
+	color := statusColor(workflow, status)
+	inner := width - 2
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(color).
+		Render(fmt.Sprintf("%s (%d)", strings.ToUpper(string(status)), len(b.columns[col])))
+	lines := []string{header, mutedStyle.Render(strings.Repeat("─", inner))}
+
+	now := time.Now()
+	tasks := b.columns[col]
+	offset, visible := b.offsets[col], b.visibleCards()
+	if offset > 0 {
+		lines[1] = mutedStyle.Render(ansi.Truncate(fmt.Sprintf("↑ %d more %s", offset, strings.Repeat("─", inner)), inner, ""))
+	}
+	for row := offset; row < len(tasks) && row < offset+visible; row++ {
+		selected := col == b.col && row == b.rows[col]
+		lines = append(lines, renderCard(tasks[row], inner, selected, color, now, workflow)...)
+	}
+	if rest := len(tasks) - offset - visible; rest > 0 {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("↓ %d more", rest)))
+	}
+
+	return lipgloss.NewStyle().Width(width).PaddingRight(2).Render(strings.Join(lines, "\n"))
+}
+
+// renderCard renders a task as two lines and a gap; the selected card is marked with a bar
+func renderCard(t task.Task, width int, selected bool, color lipgloss.Color, now time.Time, workflow *task.Workflow) []string {
+	marker := "  "
+	if selected {
+		marker = lipgloss.NewStyle().Foreground(color).Render("▌ ")
+	}
+	width -= 2
+
+	heading := fmt.Sprintf("#%d", t.ID)
+	if t.Priority != task.PriorityNone {
+		heading += " " + string(t.Priority)
+	}
+	heading = ansi.Truncate(heading+" "+t.Name, width, "…")
+	if selected {
+		heading = selectedStyle.Render(heading)
+	}
+
+	var details []string
+	if t.DueAt != nil {
+		due := "due " + t.DueAt.Format(task.DueDateLayout)
+		switch {
+		case workflow.Overdue(t, now):
+			due = overdueStyle.Render(due)
+		case workflow.DueToday(t, now):
+			due = todayStyle.Render(due)
+		default:
+			due = mutedStyle.Render(due)
+		}
+		details = append(details, due)
+	}
+	if len(t.Tags) > 0 {
+		details = append(details, mutedStyle.Render(strings.Join(t.Tags, ",")))
+	}
+	detail := ansi.Truncate(strings.Join(details, mutedStyle.Render(" · ")), width, "…")
+
+	return []string{marker + heading, marker + detail, ""}
+}
+
+func statusColor(workflow *task.Workflow, status task.TaskStatus) lipgloss.Color {
+	if def, ok := workflow.Status(status); ok {
+		if color, ok := terminalColors[def.Color]; ok {
+			return color
+		}
+	}
+	return lipgloss.Color("7")
+}
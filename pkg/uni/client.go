@@ -0,0 +1,636 @@
+package uni
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mad01/uni/internal/config"
+	"github.com/mad01/uni/internal/query"
+	"github.com/mad01/uni/internal/search"
+	"github.com/mad01/uni/internal/task"
+)
+
+// Client reads and writes the tasks of one store. It is safe for concurrent use.
+type Client struct {
+	store    *task.TaskStore
+	loc      Location
+	cfg      *config.Config
+	opts     []Option
+	workflow *Workflow
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Option configures Open
+type Option func(*options)
+
+type options struct {
+	dataDir string
+	backend string
+	project string
+	store   string
+}
+
+// WithDataDir opens the store in dir instead of searching for one, see Locate
+func WithDataDir(dir string) Option {
+	return func(o *options) {
+		o.dataDir = dir
+	}
+}
+
+// WithBackend selects the storage backend (json, sqlite, events or memory),
+// overriding the store setting of the config file
+func WithBackend(name string) Option {
+	return func(o *options) {
+		o.backend = name
+	}
+}
+
+// WithProject selects a project of the store, overriding the project setting of
+// the config file. Without it, or with DefaultProject, the tasks in the data
+// directory itself are used.
+func WithProject(name string) Option {
+	return func(o *options) {
+		o.project = name
+	}
+}
+
+// Sources of a data directory, see Location
+const (
+	// SourceOption means the directory was given with WithDataDir
+	SourceOption = "option"
+	// SourceEnv means the directory was given by the UNI_DIR environment variable
+	SourceEnv = task.SourceEnv
+	// SourceDirectory means a .uni directory was found in the working directory or above it
+	SourceDirectory = task.SourceDirectory
+	// SourceWorktree means the .uni directory of the main checkout of a git worktree is used
+	SourceWorktree = task.SourceWorktree
+	// SourceGlobal means no .uni directory was found and ~/.uni is used
+	SourceGlobal = task.SourceGlobal
+	// SourceRegistry means the directory was selected by name with WithStore
+	SourceRegistry = "registry"
+)
+
+// Location describes the store Open opens for a set of options
+type Location struct {
+	// Dir is the absolute path of the data directory
+	Dir string `json:"dir"`
+	// Source tells how Dir was chosen, one of the Source constants
+	Source string `json:"source"`
+	// Root is the git repository the search for a .uni directory stopped at, if any
+	Root string `json:"root,omitempty" yaml:"root,omitempty"`
+	// Backend is the storage backend in use
+	Backend string `json:"backend"`
+	// ConfigFile is the path of the config file of the store, which need not exist
+	ConfigFile string `json:"config_file" yaml:"config_file"`
+	// Project is the selected project, DefaultProject if none is
+	Project string `json:"project"`
+	// ProjectDir is the directory holding the tasks of Project
+	ProjectDir string `json:"project_dir" yaml:"project_dir"`
+	// Store is the registered name the store was opened by with WithStore
+	Store string `json:"store,omitempty" yaml:"store,omitempty"`
+}
+
+// Locate returns the store Open would open for opts without opening it.
+// Without WithDataDir the data directory is the one named by the UNI_DIR
+// environment variable, else the nearest .uni directory in the working
+// directory or above it up to the root of its git repository, else ~/.uni.
+// A project that does not exist fails with ErrProjectNotFound.
+func Locate(ctx context.Context, opts ...Option) (*Location, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	loc, _, err := locate(opts)
+	return loc, err
+}
+
+// locate resolves the options to a location and reads the config found there
+func locate(opts []Option) (*Location, *config.Config, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var found task.Location
+	if o.store != "" {
+		s, err := lookupStore(o.store)
+		if err != nil {
+			return nil, nil, err
+		}
+		found = task.Location{Dir: s.Dir, Source: SourceRegistry, Root: s.Root}
+	} else if o.dataDir != "" {
+		dir, err := filepath.Abs(o.dataDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		found = task.Location{Dir: dir, Source: SourceOption}
+	} else {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, nil, err
+		}
+		if found, err = task.LocateDataDir(wd); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	cfg, err := config.Load(found.Dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config: %v", err)
+	}
+
+	loc := &Location{
+		Dir:        found.Dir,
+		Source:     found.Source,
+		Root:       found.Root,
+		Backend:    o.backend,
+		ConfigFile: filepath.Join(found.Dir, config.FileName),
+		Store:      o.store,
+	}
+	if loc.Backend == "" {
+		loc.Backend = cfg.Store
+	}
+	if loc.Backend == "" {
+		loc.Backend = task.DefaultBackend
+	}
+
+	loc.Project = o.project
+	if loc.Project == "" {
+		loc.Project = cfg.Project
+	}
+	if loc.Project == "" {
+		loc.Project = task.DefaultProject
+	}
+	if !task.HasProject(loc.Dir, loc.Project) {
+		return nil, nil, fmt.Errorf("project %s: %w", loc.Project, ErrProjectNotFound)
+	}
+	loc.ProjectDir = task.ProjectDir(loc.Dir, loc.Project)
+	return loc, cfg, nil
+}
+
+// Open opens the store selected by opts, see Locate, creating its data directory
+// if needed. The config file in the data directory is applied: its backend,
+// project and workflow settings.
+func Open(ctx context.Context, opts ...Option) (*Client, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	loc, cfg, err := locate(opts)
+	if err != nil {
+		return nil, err
+	}
+	workflow := cfg.Workflow
+	if workflow == nil {
+		workflow = task.DefaultWorkflow()
+	} else if err := workflow.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow in config: %v", err)
+	}
+
+	store, err := task.OpenTaskStore(loc.ProjectDir, loc.Backend)
+	if err != nil {
+		return nil, err
+	}
+	store.SetWorkflow(workflow)
+
+	return &Client{store: store, loc: *loc, cfg: cfg, opts: opts, workflow: workflow}, nil
+}
+
+// Close releases the store. Calls made after Close fail with ErrClosed.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.store.Close()
+}
+
+// begin checks that a call may proceed; the returned function ends it
+func (c *Client) begin(ctx context.Context) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return nil, ErrClosed
+	}
+	return c.mu.RUnlock, nil
+}
+
+// Location returns where the store is and how it was found
+func (c *Client) Location() Location {
+	return c.loc
+}
+
+// DataDir returns the data directory of the store; the tasks of a named project
+// live in Location().ProjectDir below it
+func (c *Client) DataDir() string {
+	return c.loc.Dir
+}
+
+// ConfigPath returns the path of the config file of the store
+func (c *Client) ConfigPath() string {
+	return c.loc.ConfigFile
+}
+
+// Backend returns the name of the storage backend in use
+func (c *Client) Backend() string {
+	return c.loc.Backend
+}
+
+// Project returns the name of the selected project
+func (c *Client) Project() string {
+	return c.loc.Project
+}
+
+// Projects returns the projects of the store, DefaultProject first
+func (c *Client) Projects(ctx context.Context) ([]string, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return task.Projects(c.loc.Dir)
+}
+
+// AddProject creates a project in the store. Each project has its own tasks and
+// its own sequence of IDs.
+func (c *Client) AddProject(ctx context.Context, name string) error {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return task.AddProject(c.loc.Dir, name)
+}
+
+// OpenProject opens another project of the same store with the options the
+// client was opened with. The returned client must be closed separately.
+func (c *Client) OpenProject(ctx context.Context, name string) (*Client, error) {
+	opts := append(append([]Option(nil), c.opts...), WithDataDir(c.loc.Dir), WithProject(name))
+	return Open(ctx, opts...)
+}
+
+// taskProject is the project tasks read from the store carry, empty for the default project
+func (c *Client) taskProject() string {
+	if c.loc.Project == task.DefaultProject {
+		return ""
+	}
+	return c.loc.Project
+}
+
+// stamp sets the project and store on tasks read from the store
+func (c *Client) stamp(tasks ...*Task) {
+	for _, t := range tasks {
+		if t != nil {
+			t.Project = c.taskProject()
+			t.Store = c.loc.Store
+		}
+	}
+}
+
+// stampAll sets the project on a list of tasks read from the store
+func (c *Client) stampAll(tasks []Task) []Task {
+	for i := range tasks {
+		c.stamp(&tasks[i])
+	}
+	return tasks
+}
+
+// Workflow returns the statuses and transitions the tasks of the store follow
+func (c *Client) Workflow() *Workflow {
+	return c.workflow
+}
+
+// Views returns the saved views of the config file by name
+func (c *Client) Views() map[string]View {
+	return c.cfg.Views
+}
+
+// Create adds a task described by spec and returns it. A Parent that does not
+// exist fails with ErrNotFound.
+func (c *Client) Create(ctx context.Context, spec TaskSpec) (*Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	t, err := c.store.CreateTask(spec)
+	c.stamp(t)
+	return t, err
+}
+
+// Get returns the task with the given ID, or a *NotFoundError
+func (c *Client) Get(ctx context.Context, id int) (*Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	t, err := c.store.GetTask(id)
+	c.stamp(t)
+	return t, err
+}
+
+// ListOptions select and order the tasks returned by List. The zero value lists
+// all tasks by ID; every field that is set narrows the result further.
+type ListOptions struct {
+	// Query is an expression of the uni list query language, e.g. "is:left tag:backend"
+	Query string
+	// Left keeps tasks in an active status, Closed those in a closed status;
+	// setting both keeps either
+	Left   bool
+	Closed bool
+	// Statuses keeps tasks in one of these statuses
+	Statuses []TaskStatus
+	// Tags keeps tasks carrying all of these tags
+	Tags []string
+	// Parent keeps the direct subtasks of this task
+	Parent int
+	// BlockedBy keeps the tasks blocked by this task
+	BlockedBy int
+	// Match, if set, must also accept the task
+	Match func(t Task) bool
+	// Sort is one of the SortBy keys; empty sorts by ID
+	Sort string
+}
+
+// List returns the tasks selected by opts. An invalid Query fails with a *QueryError.
+func (c *Client) List(ctx context.Context, opts ListOptions) ([]Task, error) {
+	filter, err := opts.filter(c.workflow, c.stamp)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	tasks, err := c.store.FindTasks(filter)
+	if err != nil {
+		return nil, err
+	}
+	c.stampAll(tasks)
+	if err := task.SortTasks(tasks, opts.Sort); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// filter translates the options to a backend filter for the tasks of workflow
+// w; stamp prepares each task as the client returns it before it is matched
+func (opts ListOptions) filter(w *Workflow, stamp func(...*Task)) (task.Filter, error) {
+	q, err := query.ParseWorkflow(opts.Query, time.Now(), w)
+	if err != nil {
+		return task.Filter{}, err
+	}
+
+	filter := w.StatusFilter(opts.Left, opts.Closed)
+	filter.Tags = opts.Tags
+	filter.Parent = opts.Parent
+	filter.BlockedBy = opts.BlockedBy
+	filter.Match = func(t task.Task) bool {
+		stamp(&t)
+		if len(opts.Statuses) > 0 && !hasStatus(opts.Statuses, t.Status) {
+			return false
+		}
+		if opts.Match != nil && !opts.Match(t) {
+			return false
+		}
+		return q.Match(t)
+	}
+	return filter, nil
+}
+
+func hasStatus(statuses []TaskStatus, status TaskStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Update saves the name, description, tags, priority and due date of t. The task
+// must still carry the UpdatedAt it was read with, otherwise the update fails
+// with ErrConflict. On success t carries its new UpdatedAt.
+func (c *Client) Update(ctx context.Context, t *Task) error {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return c.store.UpdateTask(t)
+}
+
+// SetStatus moves a task to another status and records the transition in its
+// history. Transitions the workflow does not allow fail with ErrInvalidTransition.
+func (c *Client) SetStatus(ctx context.Context, id int, change StatusChange) (*Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	t, err := c.store.ChangeStatus(id, change)
+	c.stamp(t)
+	return t, err
+}
+
+// Batch selects the tasks of a batch change
+type Batch struct {
+	// IDs are changed in the given order; duplicates are changed once
+	IDs []int
+	// Where, if set, adds the tasks it selects after IDs, by ID; Sort is ignored
+	Where *ListOptions
+	// ContinueOnError skips tasks that fail, reporting them in their result,
+	// instead of failing the whole batch
+	ContinueOnError bool
+}
+
+// SetStatuses applies a status change to a batch of tasks in one transaction
+// and returns a result per task. Unless batch.ContinueOnError is set, the first
+// failure, such as a missing ID, fails the call and no task is changed.
+func (c *Client) SetStatuses(ctx context.Context, batch Batch, change StatusChange) ([]StatusResult, error) {
+	var filter *task.Filter
+	if batch.Where != nil {
+		f, err := batch.Where.filter(c.workflow, c.stamp)
+		if err != nil {
+			return nil, err
+		}
+		filter = &f
+	}
+
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	results, err := c.store.ChangeStatuses(batch.IDs, filter, change, batch.ContinueOnError)
+	for _, r := range results {
+		c.stamp(r.Task)
+	}
+	return results, err
+}
+
+// Delete removes a task. If updatedAt is not zero the task must still carry it,
+// otherwise the deletion fails with ErrConflict. Tasks that are the parent of or
+// block other tasks fail with ErrInUse.
+func (c *Client) Delete(ctx context.Context, id int, updatedAt time.Time) error {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return c.store.DeleteTask(id, updatedAt)
+}
+
+// Tag adds and removes tags on a task and returns the updated task
+func (c *Client) Tag(ctx context.Context, id int, add, remove []string) (*Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	t, err := c.store.TagTask(id, add, remove)
+	c.stamp(t)
+	return t, err
+}
+
+// Link records that a task is blocked by the given tasks. Links that would
+// create a cycle fail with ErrDependencyCycle.
+func (c *Client) Link(ctx context.Context, id int, blockers []int) (*Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	t, err := c.store.LinkTask(id, blockers)
+	c.stamp(t)
+	return t, err
+}
+
+// Unlink removes blocked-by links from a task
+func (c *Client) Unlink(ctx context.Context, id int, blockers []int) (*Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	t, err := c.store.UnlinkTask(id, blockers)
+	c.stamp(t)
+	return t, err
+}
+
+// OpenSubtasks returns the subtasks of a task, at any depth, that are not closed yet
+func (c *Client) OpenSubtasks(ctx context.Context, id int) ([]Task, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	tasks, err := c.store.OpenSubtasks(id)
+	return c.stampAll(tasks), err
+}
+
+// Search ranks the tasks selected by opts against search terms, best first.
+// Every term must match the name, description or a status change reason.
+// opts.Sort is ignored.
+func (c *Client) Search(ctx context.Context, terms string, opts ListOptions) ([]SearchResult, error) {
+	q, err := search.ParseQuery(terms)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := opts.filter(c.workflow, c.stamp)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	tasks, err := c.store.FindTasks(task.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	c.stampAll(tasks)
+
+	// Every task is indexed so rare words rank the same whatever opts selects
+	idx := search.NewIndex(tasks...)
+	candidates := []task.Task{}
+	for _, t := range tasks {
+		if filter.Matches(t) {
+			candidates = append(candidates, t)
+		}
+	}
+	return idx.Search(q, candidates), nil
+}
+
+// Compact folds the change log of the store into a snapshot and returns how many
+// changes were folded. Backends without a change log fail with ErrUnsupported.
+func (c *Client) Compact(ctx context.Context) (int, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+
+	compactor, ok := c.store.Backend().(task.Compactor)
+	if !ok {
+		return 0, fmt.Errorf("%s backend cannot compact: %w", c.loc.Backend, ErrUnsupported)
+	}
+	return compactor.Compact()
+}
+
+// Migrate copies all tasks of the project to another backend in the same directory and
+// returns how many were copied. The tasks of the client's backend are left as
+// they are. Unless replace is set, a target that already holds tasks fails with
+// a *StoreNotEmptyError.
+func (c *Client) Migrate(ctx context.Context, backend string, replace bool) (int, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+
+	if backend == c.loc.Backend {
+		return 0, fmt.Errorf("source and target backend are both %s", backend)
+	}
+
+	target, err := task.OpenTaskStore(c.loc.ProjectDir, backend)
+	if err != nil {
+		return 0, err
+	}
+	defer target.Close()
+
+	tasks, err := c.store.Backend().Load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s store: %v", c.loc.Backend, err)
+	}
+
+	existing, err := target.Backend().Load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s store: %v", backend, err)
+	}
+	if len(existing) > 0 && !replace {
+		return 0, &StoreNotEmptyError{Backend: backend, Tasks: len(existing)}
+	}
+
+	if err := target.Backend().Save(tasks); err != nil {
+		return 0, fmt.Errorf("failed to write %s store: %v", backend, err)
+	}
+	return len(tasks), nil
+}
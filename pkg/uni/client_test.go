@@ -0,0 +1,382 @@
+package uni
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openTestClient(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	client, err := Open(context.Background(), append([]Option{WithDataDir(t.TempDir())}, opts...)...)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestOpen_Options(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".uni")
+	client, err := Open(context.Background(), WithDataDir(dir), WithBackend("sqlite"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer client.Close()
+
+	if client.DataDir() != dir || client.Backend() != "sqlite" {
+		t.Errorf("Expected sqlite store in %s, got %s store in %s", dir, client.Backend(), client.DataDir())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tasks.db")); err != nil {
+		t.Errorf("Expected the store to be created: %v", err)
+	}
+}
+
+func TestOpen_ConfigBackend(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte("store: memory\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := openTestClient(t, WithDataDir(dir))
+	if client.Backend() != "memory" {
+		t.Errorf("Expected the backend of the config, got %s", client.Backend())
+	}
+	if client := openTestClient(t, WithDataDir(dir), WithBackend("json")); client.Backend() != "json" {
+		t.Errorf("Expected WithBackend to override the config, got %s", client.Backend())
+	}
+}
+
+func TestOpen_WorkflowPerStore(t *testing.T) {
+	dir := t.TempDir()
+	config := `workflow:
+  statuses:
+    - {name: todo, kind: active}
+    - {name: review, kind: active}
+    - {name: done, kind: closed}
+`
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	plain := openTestClient(t)
+	custom := openTestClient(t, WithDataDir(dir))
+	for _, client := range []*Client{plain, custom} {
+		client.Create(ctx, TaskSpec{Name: "Left"})
+	}
+	if _, err := plain.SetStatus(ctx, 1, StatusChange{Status: StatusWorking}); err != nil {
+		t.Fatalf("Expected the default workflow to apply to the plain store: %v", err)
+	}
+	if _, err := custom.SetStatus(ctx, 1, StatusChange{Status: StatusWorking}); err == nil {
+		t.Error("Expected working to be unknown in the custom store")
+	}
+
+	for _, client := range []*Client{plain, custom} {
+		left, err := client.List(ctx, ListOptions{Left: true, Query: "is:left"})
+		if err != nil || len(left) != 1 {
+			t.Errorf("Expected the task of %s to be left by its own workflow, got %d (%v)", client.DataDir(), len(left), err)
+		}
+	}
+	if plain.Workflow().Initial() != StatusOpen || custom.Workflow().Initial() != "todo" {
+		t.Errorf("Expected each client to keep its workflow, got %s and %s", plain.Workflow().Initial(), custom.Workflow().Initial())
+	}
+}
+
+func TestOpen_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	config := "workflow:\n  statuses:\n    - {name: todo, kind: active}\n    - {name: shipped, kind: closed}\n"
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		opts := []Option{WithDataDir(t.TempDir())}
+		if i%2 == 0 {
+			opts = []Option{WithDataDir(dir), WithBackend("memory")}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := Open(context.Background(), opts...)
+			if err != nil {
+				t.Errorf("Failed to open store: %v", err)
+				return
+			}
+			defer client.Close()
+			var buf bytes.Buffer
+			WriteTasks(&buf, []Task{{ID: 1, Status: client.Workflow().Initial()}}, "normal", FormatOptions{Workflow: client.Workflow()})
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLocate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte("store: sqlite\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loc, err := Locate(context.Background(), WithDataDir(dir))
+	if err != nil {
+		t.Fatalf("Failed to locate store: %v", err)
+	}
+	want := Location{
+		Dir: dir, Source: SourceOption, Backend: "sqlite", ConfigFile: filepath.Join(dir, "config"),
+		Project: DefaultProject, ProjectDir: dir,
+	}
+	if *loc != want {
+		t.Errorf("Expected %+v, got %+v", want, *loc)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tasks.db")); !os.IsNotExist(err) {
+		t.Errorf("Expected Locate not to open the store")
+	}
+
+	t.Setenv("UNI_DIR", dir)
+	if loc, _ := Locate(context.Background()); loc.Dir != dir || loc.Source != SourceEnv {
+		t.Errorf("Expected UNI_DIR to select the store, got %+v", loc)
+	}
+}
+
+func TestClient_Projects(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := openTestClient(t, WithDataDir(dir))
+	client.Create(ctx, TaskSpec{Name: "Default task"})
+
+	if _, err := Open(ctx, WithDataDir(dir), WithProject("infra")); !errors.Is(err, ErrProjectNotFound) {
+		t.Fatalf("Expected ErrProjectNotFound, got %v", err)
+	}
+	if err := client.AddProject(ctx, "infra"); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+	for _, name := range []string{"infra", DefaultProject, "Bad Name"} {
+		if err := client.AddProject(ctx, name); err == nil {
+			t.Errorf("Expected adding project %q to fail", name)
+		}
+	}
+
+	infra, err := client.OpenProject(ctx, "infra")
+	if err != nil {
+		t.Fatalf("Failed to open project: %v", err)
+	}
+	defer infra.Close()
+	created, err := infra.Create(ctx, TaskSpec{Name: "Infra task"})
+	if err != nil || created.ID != 1 || created.Project != "infra" {
+		t.Fatalf("Expected task 1 of project infra, got %+v (%v)", created, err)
+	}
+	if err := infra.Update(ctx, created); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	tasks, _ := client.List(ctx, ListOptions{})
+	if len(tasks) != 1 || tasks[0].Name != "Default task" || tasks[0].Project != "" {
+		t.Errorf("Expected projects to keep their tasks apart, got %+v", tasks)
+	}
+	if tasks, _ := infra.List(ctx, ListOptions{Query: "project:infra"}); len(tasks) != 1 {
+		t.Errorf("Expected project:infra to match, got %+v", tasks)
+	}
+	if names, _ := client.Projects(ctx); len(names) != 2 || names[0] != DefaultProject || names[1] != "infra" {
+		t.Errorf("Expected [default infra], got %v", names)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte("project: infra\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if client := openTestClient(t, WithDataDir(dir)); client.Project() != "infra" {
+		t.Errorf("Expected the project of the config, got %s", client.Project())
+	}
+	if client := openTestClient(t, WithDataDir(dir), WithProject(DefaultProject)); client.Project() != DefaultProject {
+		t.Errorf("Expected WithProject to override the config, got %s", client.Project())
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	ctx := context.Background()
+	client := openTestClient(t, WithBackend("memory"))
+
+	_, err := client.Get(ctx, 42)
+	var notFound *NotFoundError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &notFound) || notFound.ID != 42 {
+		t.Errorf("Expected a NotFoundError for ID 42, got %v", err)
+	}
+	if _, err := client.Create(ctx, TaskSpec{Name: "Orphan", Parent: 7}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing parent, got %v", err)
+	}
+
+	parent, _ := client.Create(ctx, TaskSpec{Name: "Parent"})
+	child, _ := client.Create(ctx, TaskSpec{Name: "Child", Parent: parent.ID})
+	if err := client.Delete(ctx, parent.ID, time.Time{}); !errors.Is(err, ErrInUse) {
+		t.Errorf("Expected ErrInUse, got %v", err)
+	}
+	if _, err := client.Link(ctx, parent.ID, []int{child.ID}); err != nil {
+		t.Fatalf("Failed to link: %v", err)
+	}
+	if _, err := client.Link(ctx, child.ID, []int{parent.ID}); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected ErrDependencyCycle, got %v", err)
+	}
+
+	stale := *child
+	if _, err := client.Tag(ctx, child.ID, []string{"backend"}, nil); err != nil {
+		t.Fatalf("Failed to tag: %v", err)
+	}
+	stale.Name = "Stale"
+	if err := client.Update(ctx, &stale); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+	if _, err := client.SetStatus(ctx, child.ID, StatusChange{Status: StatusDone, UpdatedAt: stale.UpdatedAt}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict for a stale status change, got %v", err)
+	}
+
+	_, err = client.List(ctx, ListOptions{Query: "tag:("})
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Errorf("Expected a QueryError, got %v", err)
+	}
+	if _, err := client.Compact(ctx); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestClient_Context(t *testing.T) {
+	client := openTestClient(t, WithBackend("memory"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Create(ctx, TaskSpec{Name: "Never"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if tasks, _ := client.List(context.Background(), ListOptions{}); len(tasks) != 0 {
+		t.Errorf("Expected nothing to be created, got %d tasks", len(tasks))
+	}
+	if _, err := Open(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Open to honor the context, got %v", err)
+	}
+
+	client.Close()
+	if _, err := client.Get(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestClient_List(t *testing.T) {
+	ctx := context.Background()
+	client := openTestClient(t, WithBackend("memory"))
+
+	for _, spec := range []TaskSpec{
+		{Name: "Login", Tags: []string{"backend"}, Priority: PriorityP2},
+		{Name: "Signup", Tags: []string{"backend"}, Priority: PriorityP0},
+		{Name: "Docs"},
+	} {
+		if _, err := client.Create(ctx, spec); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+	client.SetStatus(ctx, 1, StatusChange{Status: StatusDone})
+
+	cases := []struct {
+		name string
+		opts ListOptions
+		want []int
+	}{
+		{"all", ListOptions{}, []int{1, 2, 3}},
+		{"query", ListOptions{Query: "tag:backend"}, []int{1, 2}},
+		{"left", ListOptions{Left: true, Tags: []string{"backend"}}, []int{2}},
+		{"statuses", ListOptions{Statuses: []TaskStatus{StatusDone}}, []int{1}},
+		{"match", ListOptions{Match: func(t Task) bool { return strings.HasPrefix(t.Name, "S") }}, []int{2}},
+		{"sort", ListOptions{Query: "tag:backend", Sort: SortByPriority}, []int{2, 1}},
+	}
+	for _, c := range cases {
+		tasks, err := client.List(ctx, c.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var got []int
+		for _, task := range tasks {
+			got = append(got, task.ID)
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+				break
+			}
+		}
+	}
+
+	results, err := client.Search(ctx, "signup", ListOptions{Left: true})
+	if err != nil || len(results) != 1 || results[0].Task.ID != 2 {
+		t.Errorf("Expected search to find task 2, got %v (%v)", results, err)
+	}
+}
+
+func TestClient_Migrate(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := openTestClient(t, WithDataDir(dir))
+	client.Create(ctx, TaskSpec{Name: "Move me"})
+
+	n, err := client.Migrate(ctx, "sqlite", false)
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 task migrated, got %d (%v)", n, err)
+	}
+	_, err = client.Migrate(ctx, "sqlite", false)
+	var notEmpty *StoreNotEmptyError
+	if !errors.As(err, &notEmpty) || notEmpty.Tasks != 1 {
+		t.Errorf("Expected StoreNotEmptyError, got %v", err)
+	}
+
+	target := openTestClient(t, WithDataDir(dir), WithBackend("sqlite"))
+	if got, err := target.Get(ctx, 1); err != nil || got.Name != "Move me" {
+		t.Errorf("Expected the task in the sqlite store, got %v (%v)", got, err)
+	}
+}
+
+func TestWriteTasks(t *testing.T) {
+	var buf bytes.Buffer
+	tasks := []Task{{ID: 1, Name: "Write", Status: StatusOpen}}
+	if err := WriteTasks(&buf, tasks, "csv", FormatOptions{Columns: []string{"id", "name"}}); err != nil {
+		t.Fatalf("Failed to write tasks: %v", err)
+	}
+	if buf.String() != "id,name\n1,Write\n" {
+		t.Errorf("Unexpected output: %q", buf.String())
+	}
+	if err := ValidateFormat("nope"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestClient_SetStatuses(t *testing.T) {
+	ctx := context.Background()
+	client := openTestClient(t, WithBackend("memory"))
+	for _, spec := range []TaskSpec{{Name: "One"}, {Name: "Two", Tags: []string{"sprint-4"}}, {Name: "Three", Tags: []string{"sprint-4"}}} {
+		client.Create(ctx, spec)
+	}
+
+	_, err := client.SetStatuses(ctx, Batch{IDs: []int{1, 7}}, StatusChange{Status: StatusDone})
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != 7 {
+		t.Fatalf("Expected task 7 to be missing, got %v", err)
+	}
+	if got, _ := client.Get(ctx, 1); got.Status != StatusOpen {
+		t.Errorf("Expected the batch to be rolled back, got %s", got.Status)
+	}
+
+	results, err := client.SetStatuses(ctx, Batch{Where: &ListOptions{Query: "tag:sprint-4"}}, StatusChange{Status: StatusDone, Reason: "sprint over"})
+	if err != nil || len(results) != 2 || results[0].ID != 2 || results[1].Task.History[0].Reason != "sprint over" {
+		t.Errorf("Expected tasks 2 and 3 to be done, got %+v (%v)", results, err)
+	}
+	if _, err := client.SetStatuses(ctx, Batch{Where: &ListOptions{Query: "tag:("}}, StatusChange{Status: StatusDone}); err == nil {
+		t.Errorf("Expected an invalid query to fail")
+	}
+}
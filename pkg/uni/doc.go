@@ -0,0 +1,34 @@
+// Package uni is the Go API of the uni task manager. It reads and writes the
+// same task stores as the uni command, which is built on top of it.
+//
+// Open a store with Open and work with its tasks through the returned Client:
+//
+//	client, err := uni.Open(ctx, uni.WithDataDir("/path/to/repo/.uni"))
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	t, err := client.Create(ctx, uni.TaskSpec{Name: "Write release notes", Priority: uni.PriorityP1})
+//	if err != nil {
+//		return err
+//	}
+//	if _, err := client.SetStatus(ctx, t.ID, uni.StatusChange{Status: uni.StatusDone}); err != nil {
+//		return err
+//	}
+//
+//	left, err := client.List(ctx, uni.ListOptions{Query: "is:left tag:backend", Sort: uni.SortByPriority})
+//
+// Every method takes a context and fails with its error once the context is
+// done. Failures that callers typically handle are reported with the errors
+// defined in this package, to be checked with errors.Is and errors.As:
+//
+//	if errors.Is(err, uni.ErrNotFound) { ... }
+//
+// The uni command records every store it opens in a registry; Stores lists
+// them, WithStore opens one by name and ParseTaskRef reads task references
+// such as api#12.
+//
+// Tasks are rendered in the output formats of the command line with WriteTasks,
+// WriteTask and friends.
+package uni
@@ -0,0 +1,46 @@
+package uni
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mad01/uni/internal/query"
+	"github.com/mad01/uni/internal/task"
+)
+
+var (
+	// ErrNotFound is returned when a task ID does not exist; the error is a *NotFoundError
+	ErrNotFound = task.ErrNotFound
+	// ErrConflict is returned when a write would overwrite changes made since the task was read
+	ErrConflict = task.ErrConflict
+	// ErrInvalidTransition is returned when the workflow does not allow a status change
+	ErrInvalidTransition = task.ErrInvalidTransition
+	// ErrDependencyCycle is returned when a blocked-by link would make a task block itself
+	ErrDependencyCycle = task.ErrDependencyCycle
+	// ErrInUse is returned when deleting a task that is the parent of or blocks other tasks
+	ErrInUse = task.ErrInUse
+	// ErrProjectNotFound is returned when the selected project does not exist
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrStoreNotFound is returned when no store is registered under a name
+	ErrStoreNotFound = errors.New("store not registered")
+	// ErrClosed is returned by every call on a closed Client
+	ErrClosed = errors.New("client is closed")
+	// ErrUnsupported is returned when the storage backend lacks a feature, such as Compact
+	ErrUnsupported = errors.ErrUnsupported
+)
+
+// NotFoundError reports the ID of a task that does not exist
+type NotFoundError = task.NotFoundError
+
+// QueryError is a syntax or value error in a query, with the column it refers to
+type QueryError = query.Error
+
+// StoreNotEmptyError is returned by Migrate when the target backend already holds tasks
+type StoreNotEmptyError struct {
+	Backend string
+	Tasks   int
+}
+
+func (e *StoreNotEmptyError) Error() string {
+	return fmt.Sprintf("%s store already has %d tasks", e.Backend, e.Tasks)
+}
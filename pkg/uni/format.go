@@ -0,0 +1,66 @@
+package uni
+
+import (
+	"io"
+	"time"
+
+	"github.com/mad01/uni/internal/output"
+)
+
+// FormatOptions tweak how tasks are rendered. Workflow is usually that of the
+// client the tasks were read from, see Client.Workflow.
+type FormatOptions = output.Options
+
+// Output formats take the names of uni -o: normal, text, json, yaml, csv, tsv,
+// markdown, ics, template=<template> and template-file=<path>.
+
+// DefaultColumns returns the columns tabular formats show when none are selected
+func DefaultColumns() []string {
+	return append([]string(nil), output.DefaultColumns...)
+}
+
+// FormatNames returns the names of the available output formats
+func FormatNames() []string {
+	return output.FormatNames()
+}
+
+// ValidateFormat returns an error unless format names a usable output format
+func ValidateFormat(format string) error {
+	return output.ValidateFormat(format)
+}
+
+// WriteTasks writes a list of tasks to w in the given output format
+func WriteTasks(w io.Writer, tasks []Task, format string, opts FormatOptions) error {
+	return output.WriteTasks(w, tasks, format, opts)
+}
+
+// WriteTaskTree writes tasks to w with subtasks nested under their parents
+func WriteTaskTree(w io.Writer, tasks []Task, format string, opts FormatOptions) error {
+	return output.WriteTaskTree(w, tasks, format, opts)
+}
+
+// WriteStatusResults writes the per-task outcome of SetStatuses to w: one entry
+// per ID in json and yaml, a table in text, and the changed tasks otherwise
+func WriteStatusResults(w io.Writer, results []StatusResult, format string, opts FormatOptions) error {
+	return output.WriteStatusResults(w, results, format, opts)
+}
+
+// WriteTask writes a single task in detail to w
+func WriteTask(w io.Writer, t *Task, format string, opts FormatOptions) error {
+	return output.WriteTask(w, t, format, opts)
+}
+
+// WriteHistory writes the status history of a task to w
+func WriteHistory(w io.Writer, t *Task, format string, opts FormatOptions) error {
+	return output.WriteHistory(w, t, format, opts)
+}
+
+// WriteSearchResults writes search results to w
+func WriteSearchResults(w io.Writer, results []SearchResult, format string, opts FormatOptions) error {
+	return output.WriteSearchResults(w, results, format, opts)
+}
+
+// WriteHTMLReport writes a self-contained HTML page with the tasks grouped by the statuses of workflow
+func WriteHTMLReport(w io.Writer, title string, tasks []Task, now time.Time, workflow *Workflow) error {
+	return output.WriteHTMLReport(w, title, tasks, now, workflow)
+}
@@ -0,0 +1,143 @@
+package uni
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mad01/uni/internal/registry"
+)
+
+// RegisteredStore is a data directory in the store registry, see Register
+type RegisteredStore = registry.Store
+
+// WithStore opens the store registered under name, see Stores. It takes
+// precedence over WithDataDir; an unknown name fails with ErrStoreNotFound.
+func WithStore(name string) Option {
+	return func(o *options) {
+		o.store = name
+	}
+}
+
+// Stores returns the stores in the registry in ~/.uni/stores.yaml, by name
+func Stores(ctx context.Context) ([]RegisteredStore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r, err := loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return r.Stores, nil
+}
+
+// Unregister removes the store registered under name from the registry. Its
+// tasks are left as they are.
+func Unregister(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return updateRegistry(func(r *registry.Registry) (bool, error) {
+		if !r.Remove(name) {
+			return false, fmt.Errorf("store %s: %w", name, ErrStoreNotFound)
+		}
+		return true, nil
+	})
+}
+
+// Register adds the data directory of the client to the registry, named after
+// its git repository or directory, and returns the entry. Registering a store
+// again returns the existing entry.
+func (c *Client) Register(ctx context.Context) (RegisteredStore, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return RegisteredStore{}, err
+	}
+	defer end()
+
+	// Most commands run in a store that is registered already; they read the
+	// registry without taking its lock
+	r, err := loadRegistry()
+	if err != nil {
+		return RegisteredStore{}, err
+	}
+	if s, ok := r.Find(c.loc.Dir); ok {
+		return s, nil
+	}
+
+	var s RegisteredStore
+	err = updateRegistry(func(r *registry.Registry) (bool, error) {
+		var added bool
+		s, added = r.Add(c.loc.Dir, c.loc.Root)
+		return added, nil
+	})
+	return s, err
+}
+
+// lookupStore returns the registered store with the given name
+func lookupStore(name string) (RegisteredStore, error) {
+	r, err := loadRegistry()
+	if err != nil {
+		return RegisteredStore{}, err
+	}
+	s, ok := r.Lookup(name)
+	if !ok {
+		return RegisteredStore{}, fmt.Errorf("store %s: %w", name, ErrStoreNotFound)
+	}
+	return s, nil
+}
+
+func loadRegistry() (*registry.Registry, error) {
+	path, err := registry.Path()
+	if err != nil {
+		return nil, err
+	}
+	r, err := registry.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store registry: %v", err)
+	}
+	return r, nil
+}
+
+// updateRegistry applies fn to the registry while holding its lock, see registry.Update
+func updateRegistry(fn func(r *registry.Registry) (bool, error)) error {
+	path, err := registry.Path()
+	if err != nil {
+		return err
+	}
+	err = registry.Update(path, fn)
+	if errors.Is(err, ErrStoreNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update store registry: %v", err)
+	}
+	return nil
+}
+
+// TaskRef addresses a task by ID, optionally in a registered store: 12 or api#12
+type TaskRef struct {
+	// Store is the name of a registered store, empty for the current one
+	Store string
+	ID    int
+}
+
+// ParseTaskRef parses a task reference such as 12 or api#12
+func ParseTaskRef(s string) (TaskRef, error) {
+	store, id, qualified := strings.Cut(s, "#")
+	if !qualified {
+		store, id = "", s
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil || n <= 0 || (qualified && store == "") {
+		return TaskRef{}, fmt.Errorf("invalid task ID: %s", s)
+	}
+	return TaskRef{Store: store, ID: n}, nil
+}
+
+// String renders the reference as #12 or api#12
+func (r TaskRef) String() string {
+	return fmt.Sprintf("%s#%d", r.Store, r.ID)
+}
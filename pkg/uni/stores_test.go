@@ -0,0 +1,69 @@
+package uni
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), "api", ".uni")
+
+	client := openTestClient(t, WithDataDir(dir))
+	client.Create(ctx, TaskSpec{Name: "Fix login"})
+	registered, err := client.Register(ctx)
+	if err != nil || registered.Name != "api" || registered.Dir != dir {
+		t.Fatalf("Expected %s registered as api, got %+v (%v)", dir, registered, err)
+	}
+
+	api := openTestClient(t, WithStore("api"))
+	if loc := api.Location(); loc.Dir != dir || loc.Source != SourceRegistry || loc.Store != "api" {
+		t.Errorf("Expected the api store, got %+v", loc)
+	}
+	got, err := api.Get(ctx, 1)
+	if err != nil || got.Store != "api" {
+		t.Errorf("Expected task 1 of store api, got %+v (%v)", got, err)
+	}
+	if err := api.Update(ctx, got); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if got, _ := client.Get(ctx, 1); got.Store != "" {
+		t.Errorf("Expected the store name not to be saved, got %q", got.Store)
+	}
+
+	if err := Unregister(ctx, "api"); err != nil {
+		t.Fatalf("Failed to unregister: %v", err)
+	}
+	if _, err := Open(ctx, WithStore("api")); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("Expected ErrStoreNotFound, got %v", err)
+	}
+	if stores, _ := Stores(ctx); len(stores) != 0 {
+		t.Errorf("Expected no stores left, got %+v", stores)
+	}
+}
+
+func TestParseTaskRef(t *testing.T) {
+	cases := []struct {
+		in   string
+		want TaskRef
+	}{
+		{"12", TaskRef{ID: 12}},
+		{"api#12", TaskRef{Store: "api", ID: 12}},
+	}
+	for _, c := range cases {
+		if got, err := ParseTaskRef(c.in); err != nil || got != c.want {
+			t.Errorf("ParseTaskRef(%q): expected %+v, got %+v (%v)", c.in, c.want, got, err)
+		}
+	}
+	for _, in := range []string{"", "x", "#12", "api#", "api#x", "0", "-3"} {
+		if _, err := ParseTaskRef(in); err == nil {
+			t.Errorf("Expected ParseTaskRef(%q) to fail", in)
+		}
+	}
+	if s := (TaskRef{Store: "api", ID: 3}).String(); s != "api#3" {
+		t.Errorf("Expected api#3, got %s", s)
+	}
+}
@@ -0,0 +1,111 @@
+package uni
+
+import (
+	"time"
+
+	"github.com/mad01/uni/internal/config"
+	"github.com/mad01/uni/internal/query"
+	"github.com/mad01/uni/internal/search"
+	"github.com/mad01/uni/internal/task"
+)
+
+// Task is a single task
+type Task = task.Task
+
+// TaskSpec describes a task to create
+type TaskSpec = task.TaskSpec
+
+// TaskStatus is the name of a workflow status
+type TaskStatus = task.TaskStatus
+
+// Transition records a single status change of a task
+type Transition = task.Transition
+
+// StatusChange describes a requested status update
+type StatusChange = task.StatusChange
+
+// StatusResult is the outcome of a batch status change for one task, see SetStatuses
+type StatusResult = task.StatusResult
+
+// Priority ranks how urgent a task is, from P0 (most urgent) to P3
+type Priority = task.Priority
+
+// Workflow defines the statuses of a store and the transitions between them
+type Workflow = task.Workflow
+
+// View is a saved list invocation from the config file
+type View = config.View
+
+// Query is a parsed expression of the uni list query language
+type Query = query.Query
+
+// SearchResult is a task matching a search together with its score
+type SearchResult = search.Result
+
+// The statuses of the default workflow
+const (
+	StatusOpen    = task.StatusOpen
+	StatusWorking = task.StatusWorking
+	StatusBlocked = task.StatusBlocked
+	StatusDone    = task.StatusDone
+	StatusCancel  = task.StatusCancel
+)
+
+// The priorities, most urgent first
+const (
+	PriorityNone = task.PriorityNone
+	PriorityP0   = task.PriorityP0
+	PriorityP1   = task.PriorityP1
+	PriorityP2   = task.PriorityP2
+	PriorityP3   = task.PriorityP3
+)
+
+// DefaultProject names the project whose tasks live in the data directory itself
+const DefaultProject = task.DefaultProject
+
+// Sort keys accepted by ListOptions and SortTasks
+const (
+	SortByID       = task.SortByID
+	SortByPriority = task.SortByPriority
+	SortByDue      = task.SortByDue
+)
+
+// DueDateLayout is the layout due dates are shown and entered in
+const DueDateLayout = task.DueDateLayout
+
+// ParsePriority accepts P0-P3 in any case, or just the digit; an empty string or "none" means no priority
+func ParsePriority(input string) (Priority, error) {
+	return task.ParsePriority(input)
+}
+
+// ParseDue parses a due date such as 2024-06-01, today, tomorrow, fri, +3d or +2w relative to now
+func ParseDue(input string, now time.Time) (time.Time, error) {
+	return task.ParseDue(input, now)
+}
+
+// ParseQuery parses an expression of the uni list query language against the
+// default workflow. Syntax errors are *QueryError.
+func ParseQuery(input string) (*Query, error) {
+	return query.Parse(input)
+}
+
+// CheckQuery checks the syntax of a query, accepting any status; each store
+// checks the statuses against its own workflow when it runs the query
+func CheckQuery(input string) error {
+	return query.Check(input)
+}
+
+// ParseQueryWorkflow parses a query like ParseQuery, checking statuses against workflow w
+func ParseQueryWorkflow(input string, w *Workflow) (*Query, error) {
+	return query.ParseWorkflow(input, time.Now(), w)
+}
+
+// SortTasks sorts tasks in place by one of the SortBy keys
+func SortTasks(tasks []Task, key string) error {
+	return task.SortTasks(tasks, key)
+}
+
+// BackendNames returns the names of the available storage backends
+func BackendNames() []string {
+	return task.BackendNames()
+}